@@ -1,11 +1,17 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"text/template"
 	"time"
 
@@ -65,7 +71,7 @@ func main() {
 	case "migrate:status":
 		migrateStatus()
 	case "route:list":
-		listRoutes()
+		listRoutes(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("Binigo Framework %s\n", version)
 	case "help", "-h", "--help":
@@ -95,7 +101,11 @@ COMMANDS:
     migrate:rollback        Rollback the last migration
     migrate:reset           Rollback all migrations
     migrate:status          Show migration status
-    route:list              List all registered routes
+    route:list [options]    List all registered routes
+                              --json           Output as JSON
+                              --method <verb>  Only routes for this method
+                              --path <text>    Only paths containing text
+                              --sort <column>  Sort by path, method, name or handler
     version                 Show Binigo version
     help                    Show this help message
 
@@ -106,6 +116,7 @@ EXAMPLES:
     binigo make:controller User
     binigo make:model Post
     binigo migrate
+    binigo route:list --method GET --path /api
 
 DOCUMENTATION:
     https://github.com/Chisonm/binigo
@@ -134,6 +145,8 @@ func createProject(name string) {
 		"database/migrations",
 		"routes",
 		"public",
+		"resources/views/layouts",
+		"resources/views/partials",
 		"storage/logs",
 		"storage/uploads",
 	}
@@ -178,6 +191,7 @@ func createMainFile(projectName string) {
 
 import (
 	"database/sql"
+	"encoding/json"
 	"log"
 	"os"
 	"%[1]s/config"
@@ -204,6 +218,9 @@ func main() {
 		case "db:status":
 			migrationStatus()
 			return
+		case "route:list":
+			listRoutes()
+			return
 		}
 	}
 
@@ -211,6 +228,16 @@ func main() {
 	cfg := config.Load()
 
 	// Bootstrap application
+	app := newApp(cfg)
+
+	// Start server; the bound address is logged once listening
+	if err := app.Run(":" + cfg.Port); err != nil {
+		log.Fatal("❌ Server error:", err)
+	}
+}
+
+// newApp creates the application with its middleware and routes registered
+func newApp(cfg *binigo.Config) *binigo.Application {
 	app := binigo.NewApplication(cfg)
 
 	// Register global middleware
@@ -220,10 +247,29 @@ func main() {
 	// Register routes
 	routes.Register(app)
 
-	// Start server
-	log.Printf("🚀 Server starting on :%%s", cfg.Port)
-	if err := app.Run(":" + cfg.Port); err != nil {
-		log.Fatal("❌ Server error:", err)
+	// Serve public/ for paths no route matches
+	app.Static("/", "public", binigo.StaticOptions{Index: true})
+
+	return app
+}
+
+// listRoutes writes registered routes as JSON for binigo route:list, to
+// the file it names so startup logging on stdout cannot corrupt them
+func listRoutes() {
+	app := newApp(config.Load())
+
+	out := os.Stdout
+	if len(os.Args) > 2 {
+		file, err := os.Create(os.Args[2])
+		if err != nil {
+			log.Fatal("❌ Failed to list routes:", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := json.NewEncoder(out).Encode(app.Routes()); err != nil {
+		log.Fatal("❌ Failed to list routes:", err)
 	}
 }
 
@@ -340,6 +386,7 @@ func Load() *binigo.Config {
 		Environment: getEnv("APP_ENV", "development"),
 		Debug:       getEnv("APP_DEBUG", "true") == "true",
 		Port:        getEnv("APP_PORT", "8000"),
+		AppKey:      getEnv("APP_KEY", ""),
 		Database: binigo.DatabaseConfig{
 			Driver:   getEnv("DB_DRIVER", "postgres"),
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -377,6 +424,7 @@ func createEnvFile(projectName string) {
 APP_ENV=development
 APP_DEBUG=true
 APP_PORT=8000
+APP_KEY=%s
 
 DB_DRIVER=postgres
 DB_HOST=localhost
@@ -385,10 +433,20 @@ DB_DATABASE=%s
 DB_USERNAME=postgres
 DB_PASSWORD=
 `
-	content = fmt.Sprintf(content, projectName, projectName)
+	content = fmt.Sprintf(content, projectName, generateAppKey(), projectName)
 	writeFile(filepath.Join(projectName, ".env"), content)
 }
 
+// generateAppKey returns a random 32-byte key for signing and encrypting cookies
+func generateAppKey() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		fmt.Printf("❌ Failed to generate APP_KEY: %v\n", err)
+		os.Exit(1)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(key)
+}
+
 func createRoutesFile(projectName string) {
 	content := `package routes
 
@@ -543,6 +601,8 @@ The server will start at http://localhost:8000
 │   └── migrations/     # Database migrations
 ├── routes/             # Route definitions
 ├── public/             # Static files
+├── resources/
+│   └── views/         # HTML templates
 ├── storage/            # Application storage
 │   ├── logs/          # Log files
 │   └── uploads/       # Uploaded files
@@ -937,10 +997,123 @@ func migrateStatus() {
 	}
 }
 
-func listRoutes() {
+// routeInfo mirrors binigo.RouteInfo as printed by the project's main.go
+type routeInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Domain     string   `json:"domain,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`
+}
+
+func listRoutes(args []string) {
+	flags := flag.NewFlagSet("route:list", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "output routes as JSON")
+	method := flags.String("method", "", "only show routes for this HTTP method")
+	pathFilter := flags.String("path", "", "only show routes whose path contains this text")
+	sortBy := flags.String("sort", "path", "sort by path, method, name or handler")
+	_ = flags.Parse(args)
+
+	// Check if we're in a project directory
+	if _, err := os.Stat("main.go"); os.IsNotExist(err) {
+		fmt.Println("❌ Error: main.go not found")
+		fmt.Println("   Make sure you're in a Binigo project directory")
+		os.Exit(1)
+	}
+
+	// Boot the project's route registration and have it write the routes
+	// as JSON to a file; anything the app logs on stdout is ignored
+	routesFile, err := os.CreateTemp("", "binigo-routes-*.json")
+	if err != nil {
+		fmt.Printf("❌ Failed to load routes: %v\n", err)
+		os.Exit(1)
+	}
+	routesFile.Close()
+	defer os.Remove(routesFile.Name())
+
+	cmd := exec.Command("go", "run", "main.go", "route:list", routesFile.Name())
+	cmd.Stderr = os.Stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		fmt.Printf("❌ Failed to load routes: %v\n", err)
+		fmt.Println("   Note: You need to handle the route:list command in your main.go")
+		os.Exit(1)
+	}
+
+	// Projects created before the file argument print the routes instead
+	if data, err := os.ReadFile(routesFile.Name()); err == nil && len(data) > 0 {
+		output = data
+	}
+
+	var routes []routeInfo
+	if err := json.Unmarshal(output, &routes); err != nil {
+		fmt.Printf("❌ Failed to parse routes: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Apply filters
+	filtered := make([]routeInfo, 0, len(routes))
+	for _, route := range routes {
+		if *method != "" && !strings.EqualFold(route.Method, *method) {
+			continue
+		}
+		if *pathFilter != "" && !strings.Contains(route.Path, *pathFilter) {
+			continue
+		}
+		filtered = append(filtered, route)
+	}
+	routes = filtered
+
+	sortKey := func(route routeInfo) string {
+		switch *sortBy {
+		case "method":
+			return route.Method
+		case "name":
+			return route.Name
+		case "handler":
+			return route.Handler
+		default:
+			return route.Path
+		}
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return sortKey(routes[i]) < sortKey(routes[j])
+	})
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(routes); err != nil {
+			fmt.Printf("❌ Failed to encode routes: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("📋 Registered Routes:")
-	fmt.Println("--------------------------------------------------")
-	// Implementation would list actual routes
+	fmt.Println()
+
+	if len(routes) == 0 {
+		fmt.Println("No routes registered")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tNAME\tHANDLER\tMIDDLEWARE")
+	fmt.Fprintln(w, "------\t----\t----\t-------\t----------")
+	for _, route := range routes {
+		path := route.Path
+		if route.Domain != "" {
+			path = route.Domain + path
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			route.Method, path, route.Name, route.Handler, strings.Join(route.Middleware, ", "))
+	}
+	w.Flush()
+
+	fmt.Printf("\nShowing %d route(s)\n", len(routes))
 }
 
 func toSnakeCase(str string) string {
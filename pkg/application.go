@@ -1,31 +1,69 @@
 package binigo
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net"
-	"strconv"
+	"os"
+	"os/signal"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/valyala/fasthttp"
 )
 
+// DefaultShutdownTimeout is used when Config.ShutdownTimeout is not set
+const DefaultShutdownTimeout = 10 * time.Second
+
+// StartHook runs before the server starts accepting connections
+type StartHook func(*Application) error
+
+// ShutdownHook runs after the server has stopped accepting connections.
+// The context expires when the shutdown timeout is reached.
+type ShutdownHook func(ctx context.Context) error
+
 // Application is the main framework instance
 type Application struct {
-	router     *Router
-	container  *Container
-	middleware []MiddlewareFunc
-	config     *Config
-	mu         sync.RWMutex
+	router        *Router
+	container     *Container
+	middleware    []MiddlewareFunc
+	config        *Config
+	server        *fasthttp.Server
+	addr          net.Addr
+	startHooks    []StartHook
+	shutdownHooks []ShutdownHook
+	errorHandler  ErrorHandlerFunc
+	contextPool   sync.Pool
+	chain         atomic.Pointer[HandlerFunc] // global middleware around the router, nil until built
+	jsonCodec     atomic.Pointer[JSONCodec]   // set by SetJSONCodec, nil means the default
+	views         *ViewEngine                 // created on first use by Views
+	cookieKey     *cookieKeys                 // derived from Config.AppKey on first use
+	cookieKeyErr  error
+	cookieKeyOnce sync.Once
+	shutdownOnce  sync.Once
+	shutdownCtx   context.Context // cancelled when Shutdown begins
+	beginShutdown context.CancelFunc
+	shutdownErr   error
+	mu            sync.RWMutex
 }
 
 // NewApplication creates a new framework instance
 func NewApplication(config *Config) *Application {
+	shutdownCtx, beginShutdown := context.WithCancel(context.Background())
+
 	app := &Application{
-		router:     NewRouter(),
-		container:  NewContainer(),
-		middleware: make([]MiddlewareFunc, 0),
-		config:     config,
+		router:        NewRouter(),
+		container:     NewContainer(),
+		middleware:    make([]MiddlewareFunc, 0),
+		config:        config,
+		errorHandler:  DefaultErrorHandler,
+		shutdownCtx:   shutdownCtx,
+		beginShutdown: beginShutdown,
 	}
 
 	// Register core services
@@ -54,6 +92,19 @@ func (a *Application) Use(middleware ...MiddlewareFunc) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.middleware = append(a.middleware, middleware...)
+	a.chain.Store(nil)
+}
+
+// ErrorHandler replaces the handler that turns errors returned from the
+// handler chain into responses. Passing nil restores DefaultErrorHandler.
+func (a *Application) ErrorHandler(handler ErrorHandlerFunc) {
+	if handler == nil {
+		handler = DefaultErrorHandler
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.errorHandler = handler
 }
 
 // Router returns the router instance
@@ -61,86 +112,328 @@ func (a *Application) Router() *Router {
 	return a.router
 }
 
+// Routes lists registered routes with global middleware included
+func (a *Application) Routes() []RouteInfo {
+	a.mu.RLock()
+	global := make([]string, 0, len(a.middleware))
+	for _, mw := range a.middleware {
+		global = append(global, middlewareName(mw))
+	}
+	a.mu.RUnlock()
+
+	routes := a.router.Routes()
+	for i := range routes {
+		routes[i].Middleware = append(append([]string{}, global...), routes[i].Middleware...)
+	}
+	return routes
+}
+
 // Container returns the service container
 func (a *Application) Container() *Container {
 	return a.container
 }
 
-// Run starts the HTTP server
+// OnStart registers a hook that runs before the server starts accepting connections.
+// Hooks run in registration order; the first error aborts startup.
+func (a *Application) OnStart(hook StartHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.startHooks = append(a.startHooks, hook)
+}
+
+// OnShutdown registers a hook that runs during graceful shutdown.
+// Hooks run in reverse registration order so resources acquired last are released first.
+func (a *Application) OnShutdown(hook ShutdownHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.shutdownHooks = append(a.shutdownHooks, hook)
+}
+
+// Run starts the HTTP server and blocks until it is shut down. addr is
+// host:port, unix:/path/to.sock or systemd:[name] for socket activation.
 func (a *Application) Run(addr string) error {
-	handler := a.buildHandler()
+	ln, err := a.listen(addr)
+	if err != nil {
+		return err
+	}
+
+	return a.Serve(ln)
+}
 
-	// Find available port if the specified one is in use
-	finalAddr := a.findAvailablePort(addr)
+// RunTLS starts the HTTPS server and blocks until it is shut down.
+// The certificate and key files are reloaded when they change on disk.
+func (a *Application) RunTLS(addr, certFile, keyFile string) error {
+	tlsConfig, err := newTLSConfig(certFile, keyFile)
+	if err != nil {
+		return err
+	}
 
-	log.Printf("Server starting on %s", finalAddr)
-	return fasthttp.ListenAndServe(finalAddr, handler)
+	ln, err := a.listen(addr)
+	if err != nil {
+		return err
+	}
+
+	return a.serve(tls.NewListener(ln, tlsConfig))
 }
 
-// findAvailablePort checks if the port is available, if not, finds the next available one
-func (a *Application) findAvailablePort(addr string) string {
-	host, portStr, err := net.SplitHostPort(addr)
+// ServeTLS is like Serve but terminates TLS using the certificate and key
+// files, reloading them when they change on disk
+func (a *Application) ServeTLS(ln net.Listener, certFile, keyFile string) error {
+	tlsConfig, err := newTLSConfig(certFile, keyFile)
 	if err != nil {
-		// If no host specified, assume localhost
-		portStr = addr
-		if portStr[0] == ':' {
-			portStr = portStr[1:]
+		ln.Close()
+		return err
+	}
+
+	return a.serve(tls.NewListener(ln, tlsConfig))
+}
+
+// Addr returns the address the server is bound to, or nil before it starts
+// listening. With port fallback this may differ from the requested address.
+func (a *Application) Addr() net.Addr {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.addr
+}
+
+// Serve accepts connections on the listener until SIGINT/SIGTERM is received
+// or Shutdown is called, then drains in-flight requests and runs shutdown hooks
+func (a *Application) Serve(ln net.Listener) error {
+	return a.serve(ln)
+}
+
+// serve runs the server on ln until it is shut down
+func (a *Application) serve(ln net.Listener) error {
+	if err := a.runStartHooks(); err != nil {
+		ln.Close()
+		return err
+	}
+
+	a.mu.Lock()
+	a.server = a.newServer()
+	a.addr = ln.Addr()
+	server := a.server
+	a.mu.Unlock()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(ln)
+	}()
+
+	log.Printf("Server starting on %s", ln.Addr())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-serveErr:
+		// Serve returned on its own (listener failure or a Shutdown call
+		// from elsewhere); still release resources held by hooks
+		ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+		defer cancel()
+
+		shutdownErr := a.Shutdown(ctx)
+		if err != nil {
+			return err
 		}
-		host = ""
+		return shutdownErr
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down gracefully...", sig)
 	}
 
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		log.Printf("Invalid port: %s, using default 8000", portStr)
-		port = 8000
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+	defer cancel()
+
+	err := a.Shutdown(ctx)
+	<-serveErr
+	if err == nil {
+		log.Println("Server stopped")
 	}
+	return err
+}
 
-	// Try up to 100 ports
-	for i := 0; i < 100; i++ {
-		testPort := port + i
-		testAddr := fmt.Sprintf(":%d", testPort)
-		if host != "" {
-			testAddr = fmt.Sprintf("%s:%d", host, testPort)
+// Shutdown stops accepting connections, waits for in-flight requests to finish
+// until ctx expires, then runs shutdown hooks in reverse registration order.
+// It is safe to call multiple times; only the first call has any effect.
+func (a *Application) Shutdown(ctx context.Context) error {
+	a.shutdownOnce.Do(func() {
+		// Let long-lived responses such as event streams finish first
+		a.beginShutdown()
+
+		a.mu.RLock()
+		server := a.server
+		hooks := make([]ShutdownHook, len(a.shutdownHooks))
+		copy(hooks, a.shutdownHooks)
+		a.mu.RUnlock()
+
+		var errs []error
+
+		if server != nil {
+			if err := server.ShutdownWithContext(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("server shutdown: %w", err))
+			}
 		}
 
-		// Try to listen on the port
-		ln, err := net.Listen("tcp", testAddr)
-		if err == nil {
-			ln.Close()
-			if i > 0 {
-				log.Printf("Port %d is in use, using port %d instead", port, testPort)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i](ctx); err != nil {
+				errs = append(errs, err)
 			}
-			return testAddr
 		}
+
+		a.shutdownErr = errors.Join(errs...)
+	})
+
+	return a.shutdownErr
+}
+
+// shutdownContext returns a context that is cancelled when Shutdown begins
+func (a *Application) shutdownContext() context.Context {
+	return a.shutdownCtx
+}
+
+// runStartHooks executes start hooks in registration order
+func (a *Application) runStartHooks() error {
+	a.mu.RLock()
+	hooks := make([]StartHook, len(a.startHooks))
+	copy(hooks, a.startHooks)
+	a.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(a); err != nil {
+			return fmt.Errorf("start hook failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// newServer constructs the fasthttp server from Config.Server. Zero values
+// keep the fasthttp defaults.
+func (a *Application) newServer() *fasthttp.Server {
+	server := &fasthttp.Server{
+		Handler:         a.buildHandler(),
+		ErrorHandler:    a.serverErrorHandler,
+		CloseOnShutdown: true,
 	}
 
-	// If all ports are taken, return the original
-	log.Printf("Warning: Could not find available port, attempting original: %s", addr)
-	return addr
+	if a.config == nil {
+		return server
+	}
+
+	cfg := a.config.Server
+	server.Name = cfg.Name
+	server.ReadTimeout = cfg.ReadTimeout
+	server.WriteTimeout = cfg.WriteTimeout
+	server.IdleTimeout = cfg.IdleTimeout
+	server.MaxRequestBodySize = cfg.MaxRequestBodySize
+	server.Concurrency = cfg.Concurrency
+	server.ReadBufferSize = cfg.MaxHeaderSize
+	server.DisableKeepalive = cfg.DisableKeepAlive
+	server.TCPKeepalive = cfg.TCPKeepAlivePeriod > 0
+	server.TCPKeepalivePeriod = cfg.TCPKeepAlivePeriod
+
+	return server
+}
+
+// serverErrorHandler answers requests fasthttp rejects before they reach
+// the router, such as oversized bodies or headers and read timeouts
+func (a *Application) serverErrorHandler(ctx *fasthttp.RequestCtx, err error) {
+	status := fasthttp.StatusBadRequest
+
+	var headerErr *fasthttp.ErrSmallBuffer
+	var timeoutErr interface{ Timeout() bool }
+	switch {
+	case errors.Is(err, fasthttp.ErrBodyTooLarge):
+		status = fasthttp.StatusRequestEntityTooLarge
+	case errors.As(err, &headerErr):
+		status = fasthttp.StatusRequestHeaderFieldsTooLarge
+	case errors.As(err, &timeoutErr) && timeoutErr.Timeout():
+		status = fasthttp.StatusRequestTimeout
+	}
+
+	a.handleError(NewContext(ctx, a), NewHTTPError(status).Wrap(err))
+}
+
+// shutdownTimeout returns the configured graceful shutdown timeout
+func (a *Application) shutdownTimeout() time.Duration {
+	if a.config != nil && a.config.ShutdownTimeout > 0 {
+		return a.config.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
+}
+
+// Handler returns the request handler used by the server, including global
+// middleware and error handling. It lets tests drive the application
+// without binding a port.
+func (a *Application) Handler() fasthttp.RequestHandler {
+	return a.buildHandler()
 }
 
 // buildHandler creates the main request handler with middleware chain
 func (a *Application) buildHandler() fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
-		// Create framework context
-		fctx := NewContext(ctx, a)
-
-		// Build middleware chain
-		handler := a.router.Handle
-
-		// Apply middleware in reverse order
-		for i := len(a.middleware) - 1; i >= 0; i-- {
-			handler = a.middleware[i](handler)
-		}
+		fctx := a.acquireContext(ctx)
 
 		// Execute handler chain
-		if err := handler(fctx); err != nil {
-			// Error already handled by handler
-			_ = err
+		if err := a.handlerChain()(fctx); err != nil {
+			a.handleError(fctx, err)
 		}
+
+		a.releaseContext(fctx)
 	}
 }
 
+// handlerChain returns the global middleware wrapped around the router.
+// The chain is built once and rebuilt only after Use adds middleware.
+func (a *Application) handlerChain() HandlerFunc {
+	if chain := a.chain.Load(); chain != nil {
+		return *chain
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if chain := a.chain.Load(); chain != nil {
+		return *chain
+	}
+
+	var handler HandlerFunc = a.router.Handle
+
+	// Apply middleware in reverse order
+	for i := len(a.middleware) - 1; i >= 0; i-- {
+		handler = a.middleware[i](handler)
+	}
+
+	a.chain.Store(&handler)
+	return handler
+}
+
+// acquireContext returns a pooled context bound to ctx
+func (a *Application) acquireContext(ctx *fasthttp.RequestCtx) *Context {
+	c, _ := a.contextPool.Get().(*Context)
+	if c == nil {
+		c = &Context{app: a}
+	}
+	c.fastCtx = ctx
+	return c
+}
+
+// releaseContext resets c and returns it to the pool
+func (a *Application) releaseContext(c *Context) {
+	c.reset()
+	a.contextPool.Put(c)
+}
+
+// handleError renders err through the configured error handler
+func (a *Application) handleError(ctx *Context, err error) {
+	a.mu.RLock()
+	handler := a.errorHandler
+	a.mu.RUnlock()
+
+	handler(ctx, err)
+}
+
 // Group creates a route group with shared middleware/prefix
 func (a *Application) Group(prefix string, fn func(r *Router)) *Router {
 	return a.router.Group(prefix, fn)
@@ -171,6 +464,16 @@ func (a *Application) Options(path string, handler HandlerFunc) *Route {
 	return a.router.Options(path, handler)
 }
 
+// Resource registers RESTful routes for a controller
+func (a *Application) Resource(name string, controller interface{}, options ...ResourceOption) []*Route {
+	return a.router.Resource(name, controller, options...)
+}
+
+// ApiResource registers RESTful routes for a controller without create/edit
+func (a *Application) ApiResource(name string, controller interface{}, options ...ResourceOption) []*Route {
+	return a.router.ApiResource(name, controller, options...)
+}
+
 // Any registers a route for all HTTP methods
 func (a *Application) Any(path string, handler HandlerFunc) []*Route {
 	methods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"}
@@ -191,6 +494,41 @@ type Config struct {
 	Port        string
 	Database    DatabaseConfig
 	DatabaseURL string
+
+	// ShutdownTimeout bounds how long graceful shutdown waits for
+	// in-flight requests and shutdown hooks
+	ShutdownTimeout time.Duration
+
+	// Server tunes the underlying HTTP server
+	Server ServerConfig
+
+	// Views configures template rendering for Context.View
+	Views ViewConfig
+
+	// AppKey signs and encrypts cookies. Use 32 random bytes, raw or
+	// base64 encoded with a "base64:" prefix.
+	AppKey string
+
+	// Cookies are the default options for Context.Cookie; the zero value
+	// uses DefaultCookieOptions
+	Cookies CookieOptions
+}
+
+// ServerConfig holds HTTP server limits. Zero values keep the fasthttp defaults.
+type ServerConfig struct {
+	Name               string        // Server response header; empty keeps the fasthttp default
+	ReadTimeout        time.Duration // time allowed to read a full request, including the body
+	WriteTimeout       time.Duration // time allowed to write a full response
+	IdleTimeout        time.Duration // keep-alive wait for the next request; defaults to ReadTimeout
+	MaxRequestBodySize int           // bytes; fasthttp defaults to 4MB
+	Concurrency        int           // maximum concurrent connections; fasthttp defaults to 256 * 1024
+	MaxHeaderSize      int           // per-connection read buffer, which bounds request header size
+	DisableKeepAlive   bool          // close the connection after every response
+	TCPKeepAlivePeriod time.Duration // enables TCP keep-alive probes when set
+
+	// BindMode decides whether Run may move to another port when the
+	// requested one is taken. The default only allows it in development.
+	BindMode BindMode
 }
 
 type DatabaseConfig struct {
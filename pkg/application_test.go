@@ -0,0 +1,182 @@
+package binigo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func passthroughMiddleware(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) error {
+		return next(ctx)
+	}
+}
+
+func TestReleasedContextIsClean(t *testing.T) {
+	app := NewApplication(nil)
+
+	var (
+		held       *Context
+		requestCtx context.Context
+	)
+	app.Post("/users/{id}", func(ctx *Context) error {
+		held = ctx
+		requestCtx = ctx.Context()
+		ctx.Set("user", ctx.Param("id"))
+		if ctx.InputString("name") != "Ada" {
+			t.Errorf("expected input name Ada, got %q", ctx.InputString("name"))
+		}
+		return nil
+	})
+
+	fastCtx := newRequestCtx(fasthttp.MethodPost, "/users/42")
+	fastCtx.Request.Header.SetContentType("application/json")
+	fastCtx.Request.SetBodyString(`{"name":"Ada"}`)
+	app.Handler()(fastCtx)
+
+	if held == nil {
+		t.Fatal("handler did not run")
+	}
+	if held.fastCtx != nil || held.route != nil {
+		t.Error("expected the request and route to be released")
+	}
+	if len(held.params) != 0 || len(held.paramValues) != 0 {
+		t.Errorf("expected no params, got %v %v", held.params, held.paramValues)
+	}
+	if len(held.store) != 0 {
+		t.Errorf("expected an empty store, got %v", held.store)
+	}
+	if held.input != nil || held.inputErr != nil || held.inputParsed {
+		t.Error("expected the parsed input to be released")
+	}
+	if held.ctx != nil || held.cancel != nil || held.stopDisconnect != nil {
+		t.Error("expected the request context to be released")
+	}
+	if requestCtx.Err() == nil {
+		t.Error("expected the request context to be cancelled")
+	}
+
+	// The next request gets the pooled context without the old state
+	app.Get("/empty", func(ctx *Context) error {
+		if ctx.Get("user") != nil || ctx.Param("id") != "" || ctx.HasInput("name") {
+			t.Error("expected a reused context to start empty")
+		}
+		return nil
+	})
+	app.Handler()(newRequestCtx(fasthttp.MethodGet, "/empty"))
+}
+
+type requestIDKey struct{}
+
+func TestRouteTimeoutSurvivesMiddlewareContext(t *testing.T) {
+	app := NewApplication(nil)
+	app.Use(func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) error {
+			ctx.WithValue(requestIDKey{}, "req-1")
+			return next(ctx)
+		}
+	})
+
+	var (
+		deadline    time.Time
+		hasDeadline bool
+		requestID   interface{}
+		requestTime time.Time
+	)
+	handler := func(ctx *Context) error {
+		deadline, hasDeadline = ctx.Context().Deadline()
+		requestID = ctx.Context().Value(requestIDKey{})
+		requestTime = ctx.fastCtx.Time()
+		return nil
+	}
+	slow := app.Get("/slow", handler).Timeout(time.Minute)
+	app.Get("/fast", handler)
+
+	app.Handler()(newRequestCtx(fasthttp.MethodGet, "/slow"))
+	if !hasDeadline || !deadline.Equal(requestTime.Add(time.Minute)) {
+		t.Fatalf("expected a deadline one minute after the request, got %v (%v)", deadline, hasDeadline)
+	}
+	if requestID != "req-1" {
+		t.Fatalf("expected the middleware value to survive, got %v", requestID)
+	}
+
+	// Changing the timeout while serving takes effect on the next request
+	slow.Timeout(2 * time.Minute)
+	app.Handler()(newRequestCtx(fasthttp.MethodGet, "/slow"))
+	if !deadline.Equal(requestTime.Add(2 * time.Minute)) {
+		t.Fatalf("expected the updated timeout, got %v", deadline)
+	}
+
+	app.Handler()(newRequestCtx(fasthttp.MethodGet, "/fast"))
+	if hasDeadline {
+		t.Fatalf("expected no deadline without a route timeout, got %v", deadline)
+	}
+}
+
+func TestHandlerStaticRouteDoesNotAllocate(t *testing.T) {
+	app := NewApplication(nil)
+	app.Use(passthroughMiddleware, passthroughMiddleware)
+	app.Get("/health", func(ctx *Context) error { return nil })
+
+	handler := app.Handler()
+	fastCtx := newRequestCtx(fasthttp.MethodGet, "/health")
+	handler(fastCtx)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		handler(fastCtx)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %v", allocs)
+	}
+}
+
+// BenchmarkHandler compares pooled contexts and the prebuilt middleware
+// chain with a new context and chain per request, as before pooling
+func BenchmarkHandler(b *testing.B) {
+	newBenchApp := func() *Application {
+		app := NewApplication(nil)
+		app.Use(passthroughMiddleware, passthroughMiddleware, passthroughMiddleware)
+		app.Get("/health", func(ctx *Context) error { return nil })
+		app.Get("/users/{id}", func(ctx *Context) error {
+			ctx.Set("id", ctx.Param("id"))
+			return nil
+		})
+		return app
+	}
+
+	unpooled := func(a *Application) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			fctx := NewContext(ctx, a)
+
+			handler := a.router.Handle
+			for i := len(a.middleware) - 1; i >= 0; i-- {
+				handler = a.middleware[i](handler)
+			}
+
+			if err := handler(fctx); err != nil {
+				a.handleError(fctx, err)
+			}
+		}
+	}
+
+	for _, path := range []string{"/health", "/users/42"} {
+		for _, mode := range []string{"pooled", "unpooled"} {
+			b.Run(mode+path, func(b *testing.B) {
+				app := newBenchApp()
+				handler := app.Handler()
+				if mode == "unpooled" {
+					handler = unpooled(app)
+				}
+				fastCtx := newRequestCtx(fasthttp.MethodGet, path)
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					handler(fastCtx)
+				}
+			})
+		}
+	}
+}
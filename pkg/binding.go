@@ -0,0 +1,309 @@
+package binigo
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// timeLayouts are tried in order when binding a time.Time without a time_format tag
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Bind binds the request body to v based on the Content-Type header:
+// JSON (the default when no content type is sent), XML, form-urlencoded
+// and multipart forms. Form fields are matched by `form` struct tags and
+// uploaded files bind to *multipart.FileHeader or []*multipart.FileHeader
+// fields.
+func (c *Context) Bind(v interface{}) error {
+	mediaType := "application/json"
+	if contentType := string(c.fastCtx.Request.Header.ContentType()); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return NewHTTPError(fasthttp.StatusBadRequest, "Invalid Content-Type").WithCode("invalid_content_type").Wrap(err)
+		}
+		mediaType = parsed
+	}
+
+	var err error
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		err = c.jsonCodec().Unmarshal(c.fastCtx.PostBody(), v)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		err = xml.Unmarshal(c.fastCtx.PostBody(), v)
+	case mediaType == "application/x-www-form-urlencoded":
+		err = bindValues(v, "form", argsLookup(c.fastCtx.PostArgs()), nil)
+	case mediaType == "multipart/form-data":
+		form, formErr := c.fastCtx.MultipartForm()
+		if formErr != nil {
+			err = formErr
+			break
+		}
+		err = bindValues(v, "form", func(name string) []string { return form.Value[name] }, form.File)
+	default:
+		return NewHTTPError(fasthttp.StatusUnsupportedMediaType).
+			WithCode("unsupported_media_type").
+			Wrap(fmt.Errorf("unsupported content type: %s", mediaType))
+	}
+
+	if err != nil {
+		return NewHTTPError(fasthttp.StatusBadRequest, "Invalid request body").WithCode("invalid_body").Wrap(err)
+	}
+	return nil
+}
+
+// BindQuery binds query string parameters to fields tagged `query`
+func (c *Context) BindQuery(v interface{}) error {
+	return bindInput(v, "query", argsLookup(c.fastCtx.QueryArgs()))
+}
+
+// BindParams binds route parameters to fields tagged `param`
+func (c *Context) BindParams(v interface{}) error {
+	return bindInput(v, "param", func(name string) []string {
+		if value, ok := c.params[name]; ok {
+			return []string{value}
+		}
+		return nil
+	})
+}
+
+// BindHeaders binds request headers to fields tagged `header`
+func (c *Context) BindHeaders(v interface{}) error {
+	return bindInput(v, "header", func(name string) []string {
+		return bytesSlicesToStrings(c.fastCtx.Request.Header.PeekAll(name))
+	})
+}
+
+// bindInput binds values and reports conversion failures as 400 errors
+func bindInput(v interface{}, tag string, lookup func(string) []string) error {
+	if err := bindValues(v, tag, lookup, nil); err != nil {
+		return NewHTTPError(fasthttp.StatusBadRequest, "Invalid request "+tag).WithCode("invalid_" + tag).Wrap(err)
+	}
+	return nil
+}
+
+// argsLookup returns every value for a key, accepting the name[] form
+// browsers and PHP-style clients use for repeated fields
+func argsLookup(args *fasthttp.Args) func(string) []string {
+	return func(name string) []string {
+		if values := args.PeekMulti(name); len(values) > 0 {
+			return bytesSlicesToStrings(values)
+		}
+		return bytesSlicesToStrings(args.PeekMulti(name + "[]"))
+	}
+}
+
+func bytesSlicesToStrings(values [][]byte) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return result
+}
+
+// bindValues sets the fields of the struct v points to from lookup, using
+// the name in the given struct tag. Fields without the tag are left alone;
+// untagged embedded structs are walked.
+func bindValues(v interface{}, tag string, lookup func(string) []string, files map[string][]*multipart.FileHeader) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to a struct, got %T", v)
+	}
+
+	return bindStruct(value.Elem(), tag, lookup, files)
+}
+
+func bindStruct(target reflect.Value, tag string, lookup func(string) []string, files map[string][]*multipart.FileHeader) error {
+	t := target.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := target.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && field.IsExported() {
+				if err := bindStruct(fieldValue, tag, lookup, files); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Type == fileHeaderType || field.Type == reflect.SliceOf(fileHeaderType) {
+			if uploaded := files[name]; len(uploaded) > 0 {
+				if field.Type == fileHeaderType {
+					fieldValue.Set(reflect.ValueOf(uploaded[0]))
+				} else {
+					fieldValue.Set(reflect.ValueOf(uploaded))
+				}
+			}
+			continue
+		}
+
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := setField(fieldValue, values, field.Tag.Get("time_format")); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// setField converts values into field. Slices take every value, other
+// types take the first; empty values leave non-string fields unchanged.
+func setField(field reflect.Value, values []string, timeFormat string) error {
+	if field.Kind() == reflect.Slice && !field.Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), 0, len(values))
+		for _, value := range values {
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setValue(elem, value, timeFormat); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setValue(field, values[0], timeFormat)
+}
+
+// setValue converts a single string into field
+func setValue(field reflect.Value, value string, timeFormat string) error {
+	if value == "" && field.Kind() != reflect.String {
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := setValue(elem.Elem(), value, timeFormat); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) && field.Type() != timeType {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Type() {
+	case timeType:
+		parsed, err := parseTime(value, timeFormat)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	case durationType:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
+
+// parseBool accepts strconv.ParseBool values plus on/off and yes/no as
+// sent by HTML checkboxes and hand-written query strings
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "yes":
+		return true, nil
+	case "off", "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+// parseTime parses value with layout, or with the common layouts when
+// layout is empty. A time_format of "unix" parses Unix seconds.
+func parseTime(value, layout string) (time.Time, error) {
+	switch layout {
+	case "":
+	case "unix":
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	default:
+		return time.Parse(layout, value)
+	}
+
+	for _, candidate := range timeLayouts {
+		if parsed, err := time.Parse(candidate, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time", value)
+}
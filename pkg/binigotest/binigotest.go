@@ -0,0 +1,194 @@
+// Package binigotest drives a binigo Application in memory so handlers can
+// be exercised from tests without binding a port.
+//
+//	app := binigotest.New(t, bootstrapApp())
+//	app.Fake("mailer", &fakeMailer{})
+//
+//	app.Test().Post("/users").JSON(binigo.Map{"name": "Ada"}).
+//		Expect(201).
+//		JSONPath("data.id", 1)
+package binigotest
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/valyala/fasthttp"
+)
+
+// App wraps an Application for testing. Requests made through the same
+// App share a cookie jar, so sessions persist across requests.
+type App struct {
+	*binigo.Application
+	t       testing.TB
+	handler fasthttp.RequestHandler
+	jar     *CookieJar
+}
+
+// New wraps app for testing. Failed expectations are reported through t.
+func New(t testing.TB, app *binigo.Application) *App {
+	return &App{
+		Application: app,
+		t:           t,
+		jar:         NewCookieJar(),
+	}
+}
+
+// Test starts a new request against the application
+func (a *App) Test() *Request {
+	return &Request{app: a, req: &fasthttp.Request{}}
+}
+
+// Jar returns the cookie jar shared by requests made through a
+func (a *App) Jar() *CookieJar {
+	return a.jar
+}
+
+// Fake replaces a container binding with fake for the rest of the test.
+// The original binding is restored when the test finishes.
+func (a *App) Fake(abstract string, fake interface{}) {
+	restore := a.Container().Swap(abstract, fake)
+	a.t.Cleanup(restore)
+}
+
+// serve runs req through the application handler and returns the response
+func (a *App) serve(req *fasthttp.Request) *fasthttp.Response {
+	// The handler is built on first use so routes and middleware registered
+	// after New are picked up
+	if a.handler == nil {
+		a.handler = a.Handler()
+	}
+
+	a.jar.apply(req)
+
+	var ctx fasthttp.RequestCtx
+	ctx.Init(req, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 40000}, nil)
+	a.handler(&ctx)
+
+	resp := &fasthttp.Response{}
+	// Body drains streamed responses before the copy
+	ctx.Response.Body()
+	ctx.Response.CopyTo(resp)
+	if ctx.Response.SkipBody {
+		// HEAD responses are written without their body
+		resp.ResetBody()
+	}
+
+	a.jar.store(resp)
+	return resp
+}
+
+// CookieJar stores cookies set by responses and sends them with later requests
+type CookieJar struct {
+	cookies map[string]*fasthttp.Cookie
+	mu      sync.Mutex
+}
+
+// NewCookieJar creates an empty cookie jar
+func NewCookieJar() *CookieJar {
+	return &CookieJar{cookies: make(map[string]*fasthttp.Cookie)}
+}
+
+// Get returns the value of the named cookie
+func (j *CookieJar) Get(name string) (string, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	cookie, ok := j.cookies[name]
+	if !ok || expired(cookie) {
+		return "", false
+	}
+	return string(cookie.Value()), true
+}
+
+// Set adds a cookie that is sent with every request
+func (j *CookieJar) Set(name, value string) {
+	cookie := &fasthttp.Cookie{}
+	cookie.SetKey(name)
+	cookie.SetValue(value)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies[name] = cookie
+}
+
+// Clear removes every cookie from the jar
+func (j *CookieJar) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies = make(map[string]*fasthttp.Cookie)
+}
+
+// apply adds cookies matching the request path to req
+func (j *CookieJar) apply(req *fasthttp.Request) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	path := string(req.URI().Path())
+	for name, cookie := range j.cookies {
+		if expired(cookie) {
+			delete(j.cookies, name)
+			continue
+		}
+		if cookiePath := string(cookie.Path()); cookiePath != "" && !strings.HasPrefix(path, cookiePath) {
+			continue
+		}
+		// Cookies set explicitly on the request take precedence
+		if len(req.Header.Cookie(name)) == 0 {
+			req.Header.SetCookieBytesKV(cookie.Key(), cookie.Value())
+		}
+	}
+}
+
+// store records the cookies set by resp, removing deleted ones
+func (j *CookieJar) store(resp *fasthttp.Response) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for key, value := range resp.Header.Cookies() {
+		name := string(key)
+
+		cookie := &fasthttp.Cookie{}
+		if err := cookie.ParseBytes(value); err != nil || zeroMaxAge(value) {
+			delete(j.cookies, name)
+			continue
+		}
+
+		if expired(cookie) || len(cookie.Value()) == 0 {
+			delete(j.cookies, name)
+			continue
+		}
+
+		// Track max-age as an absolute expiry so later lookups can honour it
+		if maxAge := cookie.MaxAge(); maxAge > 0 {
+			cookie.SetExpire(time.Now().Add(time.Duration(maxAge) * time.Second))
+			cookie.SetMaxAge(0)
+		}
+		j.cookies[name] = cookie
+	}
+}
+
+// zeroMaxAge reports whether a Set-Cookie value carries Max-Age=0, which
+// fasthttp parses the same as an absent Max-Age
+func zeroMaxAge(value []byte) bool {
+	for _, attr := range strings.Split(string(value), ";")[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(attr), "=")
+		if strings.EqualFold(key, "max-age") && strings.TrimSpace(val) == "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// expired reports whether cookie has been deleted or has expired
+func expired(cookie *fasthttp.Cookie) bool {
+	if cookie.MaxAge() < 0 {
+		return true
+	}
+	expire := cookie.Expire()
+	return expire != fasthttp.CookieExpireUnlimited && !expire.After(time.Now())
+}
@@ -0,0 +1,364 @@
+package binigotest_test
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+)
+
+// recorder collects assertion failures so tests can check how they are reported
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) expectFailure(t *testing.T, contains string) {
+	t.Helper()
+	for _, err := range r.errors {
+		if strings.Contains(err, contains) {
+			return
+		}
+	}
+	t.Errorf("expected a failure containing %q, got %q", contains, r.errors)
+}
+
+func newApp() *binigo.Application {
+	return binigo.NewApplication(&binigo.Config{
+		AppKey: "base64:" + "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+	})
+}
+
+func TestExpectReportsStatusMismatch(t *testing.T) {
+	app := newApp()
+	app.Post("/users", func(ctx *binigo.Context) error {
+		return ctx.Status(201).JSON(binigo.Map{"id": 1})
+	})
+
+	rec := &recorder{}
+	binigotest.New(rec, app).Test().Post("/users").Expect(200)
+
+	if len(rec.errors) != 1 {
+		t.Fatalf("expected one failure, got %q", rec.errors)
+	}
+	rec.expectFailure(t, "expected status 200, got 201")
+	rec.expectFailure(t, `{"id":1}`)
+}
+
+func TestJSONPath(t *testing.T) {
+	app := newApp()
+	app.Get("/users", func(ctx *binigo.Context) error {
+		return ctx.JSON(binigo.Map{
+			"data": binigo.Map{
+				"total": 2,
+				"items": []binigo.Map{{"name": "Ada"}, {"name": "Grace"}},
+			},
+		})
+	})
+	app.Get("/text", func(ctx *binigo.Context) error {
+		return ctx.String("plain")
+	})
+
+	binigotest.New(t, app).Test().Get("/users").
+		Expect(200).
+		JSONPath("data.total", 2).
+		JSONPath("data.items.1.name", "Grace").
+		JSONPath("data.items", []binigo.Map{{"name": "Ada"}, {"name": "Grace"}})
+
+	rec := &recorder{}
+	binigotest.New(rec, app).Test().Get("/users").
+		Expect(200).
+		JSONPath("data.total", 3).
+		JSONPath("data.items.2.name", "Linus").
+		JSONPath("data.missing", nil)
+	if len(rec.errors) != 3 {
+		t.Fatalf("expected three failures, got %q", rec.errors)
+	}
+	rec.expectFailure(t, "expected JSON path data.total to be 3, got 2")
+	rec.expectFailure(t, "JSON path data.items.2.name not found")
+	rec.expectFailure(t, "JSON path data.missing not found")
+
+	rec = &recorder{}
+	binigotest.New(rec, app).Test().Get("/text").Do().JSONPath("data", nil)
+	rec.expectFailure(t, "decode JSON response")
+}
+
+func TestCookieJarPersistsAcrossRequests(t *testing.T) {
+	app := newApp()
+	app.Post("/login", func(ctx *binigo.Context) error {
+		ctx.Cookie("session", "abc123", 3600)
+		return ctx.String("ok")
+	})
+	app.Get("/me", func(ctx *binigo.Context) error {
+		return ctx.String("%s", ctx.GetCookie("session"))
+	})
+	app.Post("/logout", func(ctx *binigo.Context) error {
+		ctx.ClearCookie("session")
+		return ctx.String("bye")
+	})
+
+	ta := binigotest.New(t, app)
+
+	ta.Test().Post("/login").Expect(200).Cookie("session", "abc123")
+	if value, ok := ta.Jar().Get("session"); !ok || value != "abc123" {
+		t.Fatalf("expected the jar to hold session=abc123, got %q", value)
+	}
+
+	ta.Test().Get("/me").Expect(200).Contains("abc123")
+	ta.Test().Get("/me").Cookie("session", "override").Expect(200).Contains("override")
+
+	ta.Test().Post("/logout").Expect(200)
+	if _, ok := ta.Jar().Get("session"); ok {
+		t.Fatal("expected the cleared cookie to leave the jar")
+	}
+	if body := string(ta.Test().Get("/me").Expect(200).Body()); body != "" {
+		t.Fatalf("expected no session after logout, got %q", body)
+	}
+}
+
+type mailer interface {
+	Name() string
+}
+
+type namedMailer string
+
+func (m namedMailer) Name() string { return string(m) }
+
+func TestFakeRestoresBinding(t *testing.T) {
+	app := newApp()
+	app.Container().Singleton("mailer", func(c *binigo.Container) interface{} {
+		return namedMailer("smtp")
+	})
+	app.Get("/mailer", func(ctx *binigo.Context) error {
+		return ctx.String("%s", ctx.Container().MustMake("mailer").(mailer).Name())
+	})
+
+	ta := binigotest.New(t, app)
+
+	t.Run("fake", func(t *testing.T) {
+		binigotest.New(t, app).Fake("mailer", namedMailer("fake"))
+		ta.Test().Get("/mailer").Expect(200).Contains("fake")
+	})
+
+	ta.Test().Get("/mailer").Expect(200).Contains("smtp")
+}
+
+func TestRouting(t *testing.T) {
+	app := newApp()
+	app.Get("/users/{id:int}", func(ctx *binigo.Context) error {
+		return ctx.String("user %s", ctx.Param("id"))
+	})
+	app.Post("/users", func(ctx *binigo.Context) error {
+		return ctx.Status(201).String("created")
+	})
+
+	ta := binigotest.New(t, app)
+	ta.Test().Get("/users/7").Expect(200).Contains("user 7")
+	ta.Test().Get("/users/ada").Expect(404).JSONPath("code", "not_found")
+	ta.Test().Delete("/users").Expect(405).
+		Header("Allow", "OPTIONS, POST").
+		JSONPath("code", "method_not_allowed")
+	ta.Test().Options("/users/7").Expect(204).Header("Allow", "GET, HEAD, OPTIONS")
+
+	if body := ta.Test().Head("/users/7").Expect(200).Body(); len(body) != 0 {
+		t.Fatalf("expected HEAD to send no body, got %q", body)
+	}
+}
+
+func TestHTTPErrors(t *testing.T) {
+	app := newApp()
+	app.Get("/teapot", func(ctx *binigo.Context) error {
+		return binigo.NewHTTPError(418, "No coffee").WithCode("teapot").WithDetails(binigo.Map{"brew": "tea"})
+	})
+	app.Get("/boom", func(ctx *binigo.Context) error {
+		return fmt.Errorf("database on fire")
+	})
+
+	ta := binigotest.New(t, app)
+	ta.Test().Get("/teapot").Expect(418).
+		JSONPath("success", false).
+		JSONPath("error", "No coffee").
+		JSONPath("code", "teapot").
+		JSONPath("details.brew", "tea")
+
+	resp := ta.Test().Get("/boom").Expect(500).JSONPath("code", "internal_error")
+	if strings.Contains(string(resp.Body()), "database on fire") {
+		t.Fatalf("expected the cause to stay hidden outside debug mode, got %s", resp.Body())
+	}
+}
+
+func TestBindAndInput(t *testing.T) {
+	type signup struct {
+		Name  string `json:"name" form:"name"`
+		Age   int    `json:"age" form:"age"`
+		Admin bool   `json:"admin" form:"admin"`
+	}
+
+	app := newApp()
+	app.Post("/bind", func(ctx *binigo.Context) error {
+		var input signup
+		if err := ctx.Bind(&input); err != nil {
+			return err
+		}
+		return ctx.JSON(input)
+	})
+	app.Post("/input", func(ctx *binigo.Context) error {
+		return ctx.JSON(binigo.Map{
+			"name":    ctx.InputString("user.name"),
+			"age":     ctx.InputInt("user.age"),
+			"missing": ctx.InputInt("user.missing", 9),
+			"has":     ctx.HasInput("user.name"),
+			"only":    ctx.Only("user"),
+		})
+	})
+
+	ta := binigotest.New(t, app)
+
+	ta.Test().Post("/bind").JSON(binigo.Map{"name": "Ada", "age": 36, "admin": true}).
+		Expect(200).
+		JSONPath("name", "Ada").
+		JSONPath("age", 36).
+		JSONPath("admin", true)
+
+	ta.Test().Post("/bind").Form(url.Values{"name": {"Grace"}, "age": {"45"}}).
+		Expect(200).
+		JSONPath("name", "Grace").
+		JSONPath("age", 45)
+
+	ta.Test().Post("/bind").Form(url.Values{"age": {"old"}}).Expect(400)
+	ta.Test().Post("/bind").Body("application/json", []byte("{")).Expect(400)
+
+	ta.Test().Post("/input").JSON(binigo.Map{"user": binigo.Map{"name": "Ada", "age": "36"}}).
+		Expect(200).
+		JSONPath("name", "Ada").
+		JSONPath("age", 36).
+		JSONPath("missing", 9).
+		JSONPath("has", true).
+		JSONPath("only.user.name", "Ada")
+}
+
+func TestSignedAndEncryptedCookies(t *testing.T) {
+	app := newApp()
+	app.Post("/remember", func(ctx *binigo.Context) error {
+		if err := ctx.SignedCookie("theme", "dark"); err != nil {
+			return err
+		}
+		return ctx.EncryptedCookie("cart", "42 items")
+	})
+	app.Get("/recall", func(ctx *binigo.Context) error {
+		theme, err := ctx.GetSignedCookie("theme")
+		if err != nil {
+			return binigo.NewHTTPError(400).WithCode("theme").Wrap(err)
+		}
+		cart, err := ctx.GetEncryptedCookie("cart")
+		if err != nil {
+			return binigo.NewHTTPError(400).WithCode("cart").Wrap(err)
+		}
+		return ctx.String("%s %s", theme, cart)
+	})
+
+	ta := binigotest.New(t, app)
+	ta.Test().Post("/remember").Expect(200)
+
+	cart, _ := ta.Jar().Get("cart")
+	if strings.Contains(cart, "42") {
+		t.Fatalf("expected the encrypted cookie to hide its value, got %q", cart)
+	}
+
+	ta.Test().Get("/recall").Expect(200).Contains("dark 42 items")
+
+	theme, _ := ta.Jar().Get("theme")
+	ta.Test().Get("/recall").Cookie("theme", strings.Replace(theme, "ZGFyaw", "bGlnaHQ", 1)).
+		Expect(400).JSONPath("code", "theme")
+	ta.Test().Get("/recall").Cookie("cart", cart[:len(cart)-2]+"AA").
+		Expect(400).JSONPath("code", "cart")
+}
+
+func TestNegotiate(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id" xml:"id"`
+		Name string `json:"name" xml:"name"`
+	}
+	users := []user{{1, "Ada"}, {2, "Grace"}}
+
+	app := newApp()
+	app.Get("/users", func(ctx *binigo.Context) error {
+		return ctx.Negotiate(binigo.Map{
+			"application/json": users,
+			"text/csv":         users,
+			"application/yaml": users,
+		})
+	})
+
+	ta := binigotest.New(t, app)
+	ta.Test().Get("/users").Expect(200).
+		Header("Content-Type", "application/json").
+		Header("Vary", "Accept").
+		JSONPath("1.name", "Grace")
+	ta.Test().Get("/users").Header("Accept", "text/csv;q=0.9, application/json;q=0.5").Expect(200).
+		Header("Content-Type", "text/csv; charset=utf-8").
+		Contains("id,name\n1,Ada\n2,Grace\n")
+	ta.Test().Get("/users").Header("Accept", "application/*;q=0.5, application/yaml").Expect(200).
+		Contains("- id: 1\n  name: Ada\n")
+	ta.Test().Get("/users").Header("Accept", "application/xml").Expect(406).
+		JSONPath("code", "not_acceptable").
+		JSONPath("details.available", []string{"application/json", "application/yaml", "text/csv"})
+}
+
+func TestSSE(t *testing.T) {
+	app := newApp()
+	app.Get("/events", func(ctx *binigo.Context) error {
+		return ctx.SSE(func(stream *binigo.SSEStream) error {
+			for i := 1; i <= 2; i++ {
+				if err := stream.Send("tick", fmt.Sprint(i), binigo.Map{"n": i}); err != nil {
+					return err
+				}
+			}
+			return stream.Send("", "", "multi\nline")
+		})
+	})
+
+	binigotest.New(t, app).Test().Get("/events").Expect(200).
+		Header("Content-Type", "text/event-stream").
+		Header("Cache-Control", "no-cache").
+		Contains("id: 1\nevent: tick\ndata: {\"n\":1}\n\n").
+		Contains("id: 2\nevent: tick\ndata: {\"n\":2}\n\n").
+		Contains("data: multi\ndata: line\n\n")
+}
+
+func TestValidationErrors(t *testing.T) {
+	app := newApp()
+	app.Post("/users", func(ctx *binigo.Context) error {
+		return ctx.ValidateRequest(map[string][]string{
+			"name":  {"required"},
+			"email": {"required", "email"},
+		})
+	})
+
+	binigotest.New(t, app).Test().Post("/users").JSON(binigo.Map{"email": "nope"}).
+		Expect(422).
+		JSONPath("success", false).
+		JSONPath("message", "Validation failed").
+		JSONPath("code", "validation_failed").
+		JSONPath("errors.name.0", "The name field is required")
+
+	err := &binigo.ValidationError{Errors: map[string][]string{
+		"name":  {"name is required"},
+		"email": {"email is invalid"},
+		"age":   {},
+	}}
+	for i := 0; i < 10; i++ {
+		if got := err.Error(); got != "validation failed: email is invalid" {
+			t.Fatalf("expected the first field's message, got %q", got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package binigotest
+
+import (
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Request builds a request against the application. It is sent by Do or Expect.
+type Request struct {
+	app *App
+	req *fasthttp.Request
+}
+
+// Get sets the method to GET and the request URI to path
+func (r *Request) Get(path string) *Request {
+	return r.Method(fasthttp.MethodGet, path)
+}
+
+// Post sets the method to POST and the request URI to path
+func (r *Request) Post(path string) *Request {
+	return r.Method(fasthttp.MethodPost, path)
+}
+
+// Put sets the method to PUT and the request URI to path
+func (r *Request) Put(path string) *Request {
+	return r.Method(fasthttp.MethodPut, path)
+}
+
+// Patch sets the method to PATCH and the request URI to path
+func (r *Request) Patch(path string) *Request {
+	return r.Method(fasthttp.MethodPatch, path)
+}
+
+// Delete sets the method to DELETE and the request URI to path
+func (r *Request) Delete(path string) *Request {
+	return r.Method(fasthttp.MethodDelete, path)
+}
+
+// Options sets the method to OPTIONS and the request URI to path
+func (r *Request) Options(path string) *Request {
+	return r.Method(fasthttp.MethodOptions, path)
+}
+
+// Head sets the method to HEAD and the request URI to path
+func (r *Request) Head(path string) *Request {
+	return r.Method(fasthttp.MethodHead, path)
+}
+
+// Method sets the request method and URI
+func (r *Request) Method(method, path string) *Request {
+	r.req.Header.SetMethod(method)
+	r.req.SetRequestURI(path)
+	return r
+}
+
+// Header sets a request header
+func (r *Request) Header(key, value string) *Request {
+	r.req.Header.Set(key, value)
+	return r
+}
+
+// Host sets the Host header used for domain routing
+func (r *Request) Host(host string) *Request {
+	r.req.Header.SetHost(host)
+	return r
+}
+
+// Query adds a query string parameter
+func (r *Request) Query(key, value string) *Request {
+	r.req.URI().QueryArgs().Add(key, value)
+	return r
+}
+
+// Cookie sends a cookie with this request only
+func (r *Request) Cookie(name, value string) *Request {
+	r.req.Header.SetCookie(name, value)
+	return r
+}
+
+// JSON encodes body as the JSON request body
+func (r *Request) JSON(body interface{}) *Request {
+	data, err := r.app.JSONCodec().Marshal(body)
+	if err != nil {
+		r.app.t.Helper()
+		r.app.t.Fatalf("binigotest: encode JSON body: %v", err)
+	}
+
+	r.req.Header.SetContentType("application/json")
+	r.req.SetBody(data)
+	return r
+}
+
+// Form sets a form-urlencoded request body
+func (r *Request) Form(values url.Values) *Request {
+	r.req.Header.SetContentType("application/x-www-form-urlencoded")
+	r.req.SetBodyString(values.Encode())
+	return r
+}
+
+// Body sets a raw request body with the given content type
+func (r *Request) Body(contentType string, body []byte) *Request {
+	r.req.Header.SetContentType(contentType)
+	r.req.SetBody(body)
+	return r
+}
+
+// Do sends the request and returns the response
+func (r *Request) Do() *Response {
+	return &Response{t: r.app.t, resp: r.app.serve(r.req), json: r.app.JSONCodec()}
+}
+
+// Expect sends the request and asserts the response status
+func (r *Request) Expect(status int) *Response {
+	r.app.t.Helper()
+	return r.Do().Status(status)
+}
@@ -0,0 +1,159 @@
+package binigotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/valyala/fasthttp"
+)
+
+// Response is a completed response with chainable assertions. Failed
+// assertions are reported with t.Errorf so every mismatch is listed.
+type Response struct {
+	t    testing.TB
+	resp *fasthttp.Response
+	json binigo.JSONCodec
+}
+
+// Raw returns the underlying fasthttp response
+func (r *Response) Raw() *fasthttp.Response {
+	return r.resp
+}
+
+// Code returns the response status code
+func (r *Response) Code() int {
+	return r.resp.StatusCode()
+}
+
+// Body returns the response body
+func (r *Response) Body() []byte {
+	return r.resp.Body()
+}
+
+// Decode unmarshals the JSON response body into v with the application's codec
+func (r *Response) Decode(v interface{}) *Response {
+	r.t.Helper()
+	if err := r.json.Unmarshal(r.resp.Body(), v); err != nil {
+		r.t.Errorf("binigotest: decode JSON response: %v\nbody: %s", err, r.resp.Body())
+	}
+	return r
+}
+
+// Status asserts the response status code
+func (r *Response) Status(status int) *Response {
+	r.t.Helper()
+	if got := r.resp.StatusCode(); got != status {
+		r.t.Errorf("expected status %d, got %d\nbody: %s", status, got, r.resp.Body())
+	}
+	return r
+}
+
+// Header asserts the value of a response header
+func (r *Response) Header(key, value string) *Response {
+	r.t.Helper()
+	if got := string(r.resp.Header.Peek(key)); got != value {
+		r.t.Errorf("expected header %s to be %q, got %q", key, value, got)
+	}
+	return r
+}
+
+// Contains asserts that the response body contains s
+func (r *Response) Contains(s string) *Response {
+	r.t.Helper()
+	if !bytes.Contains(r.resp.Body(), []byte(s)) {
+		r.t.Errorf("expected body to contain %q\nbody: %s", s, r.resp.Body())
+	}
+	return r
+}
+
+// JSONPath asserts the value at a dot-separated path in the JSON body,
+// e.g. "data.id" or "data.items.0.name". Values are compared after a
+// JSON round trip, so JSONPath("data.id", 1) matches a decoded 1.0.
+func (r *Response) JSONPath(path string, expected interface{}) *Response {
+	r.t.Helper()
+
+	var body interface{}
+	if err := json.Unmarshal(r.resp.Body(), &body); err != nil {
+		r.t.Errorf("binigotest: decode JSON response: %v\nbody: %s", err, r.resp.Body())
+		return r
+	}
+
+	got, ok := lookupPath(body, path)
+	if !ok {
+		r.t.Errorf("JSON path %s not found\nbody: %s", path, r.resp.Body())
+		return r
+	}
+
+	want, err := normalizeJSON(expected)
+	if err != nil {
+		r.t.Errorf("binigotest: encode expected value for %s: %v", path, err)
+		return r
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		r.t.Errorf("expected JSON path %s to be %#v, got %#v", path, want, got)
+	}
+	return r
+}
+
+// Cookie asserts that the response sets the named cookie to value
+func (r *Response) Cookie(name, value string) *Response {
+	r.t.Helper()
+
+	cookie := &fasthttp.Cookie{}
+	cookie.SetKey(name)
+	if !r.resp.Header.Cookie(cookie) {
+		r.t.Errorf("expected cookie %s to be set", name)
+		return r
+	}
+
+	if got := string(cookie.Value()); got != value {
+		r.t.Errorf("expected cookie %s to be %q, got %q", name, value, got)
+	}
+	return r
+}
+
+// lookupPath walks decoded JSON along a dot-separated path
+func lookupPath(value interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			next, ok := current[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// normalizeJSON converts v to the form encoding/json decodes into interface{}
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
@@ -223,6 +223,38 @@ func (c *Container) Forget(abstract string) {
 	delete(c.instances, abstract)
 }
 
+// Swap replaces a binding with instance and returns a function that
+// restores the previous binding. Intended for swapping in fakes in tests.
+func (c *Container) Swap(abstract string, instance interface{}) (restore func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if alias, ok := c.aliases[abstract]; ok {
+		abstract = alias
+	}
+
+	previousBinding, hadBinding := c.bindings[abstract]
+	previousInstance, hadInstance := c.instances[abstract]
+
+	delete(c.bindings, abstract)
+	c.instances[abstract] = instance
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.bindings, abstract)
+		delete(c.instances, abstract)
+
+		if hadBinding {
+			c.bindings[abstract] = previousBinding
+		}
+		if hadInstance {
+			c.instances[abstract] = previousInstance
+		}
+	}
+}
+
 // Flush clears all bindings and instances
 func (c *Container) Flush() {
 	c.mu.Lock()
@@ -1,20 +1,35 @@
 package binigo
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"mime/multipart"
+	"time"
 
 	"github.com/valyala/fasthttp"
 )
 
-// Context wraps fasthttp context with helper methods
+// Context wraps fasthttp context with helper methods.
+//
+// Contexts are pooled and reused once the handler chain returns, so a
+// Context must not be retained or used from other goroutines after that.
 type Context struct {
-	fastCtx *fasthttp.RequestCtx
-	app     *Application
-	params  map[string]string
-	route   *Route
-	store   map[string]interface{} // For storing data during request lifecycle
+	fastCtx     *fasthttp.RequestCtx
+	app         *Application
+	params      map[string]string // allocated on first parameter
+	route       *Route
+	store       map[string]interface{} // For storing data during request lifecycle, allocated on first Set
+	paramValues []string               // reusable buffer for route matching
+
+	// JSON body, parsed once on first use by the input helpers
+	input       Map
+	inputErr    error
+	inputParsed bool
+
+	// Request context, created on first use by Context()
+	ctx            context.Context
+	cancel         context.CancelFunc
+	stopDisconnect func()
 }
 
 // NewContext creates a new context instance
@@ -22,11 +37,74 @@ func NewContext(ctx *fasthttp.RequestCtx, app *Application) *Context {
 	return &Context{
 		fastCtx: ctx,
 		app:     app,
-		params:  make(map[string]string),
-		store:   make(map[string]interface{}),
 	}
 }
 
+// reset clears request state so the context can be reused. The maps keep
+// their capacity to avoid reallocating them on the next request.
+func (c *Context) reset() {
+	if c.cancel != nil {
+		c.stopDisconnect()
+		c.cancel()
+		c.ctx, c.cancel, c.stopDisconnect = nil, nil, nil
+	}
+
+	c.fastCtx = nil
+	c.route = nil
+	c.input, c.inputErr, c.inputParsed = nil, nil, false
+	c.paramValues = c.paramValues[:0]
+	clear(c.params)
+	clear(c.store)
+}
+
+// Context returns the request's context.Context. It is cancelled when the
+// client disconnects, when the route's Timeout expires and when the request
+// finishes, so it can be passed to database and outbound calls:
+//
+//	db.WithContext(ctx.Context()).Table("users").Get(&users)
+func (c *Context) Context() context.Context {
+	if c.ctx == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.ctx, c.cancel = ctx, cancel
+		c.stopDisconnect = watchDisconnect(c.fastCtx.Conn(), cancel)
+	}
+	return c.ctx
+}
+
+// setDeadline derives the request context with a deadline, keeping the
+// values and cancellation of the current one
+func (c *Context) setDeadline(deadline time.Time) {
+	ctx, cancelDeadline := context.WithDeadline(c.Context(), deadline)
+	parentCancel := c.cancel
+
+	c.ctx = ctx
+	c.cancel = func() {
+		cancelDeadline()
+		parentCancel()
+	}
+}
+
+// WithValue adds a request-scoped value to the request context
+func (c *Context) WithValue(key, value interface{}) {
+	c.SetContext(context.WithValue(c.Context(), key, value))
+}
+
+// SetContext replaces the request context, e.g. with one derived from
+// Context() that carries additional values. The request's cancellation
+// still applies when ctx is derived from Context().
+func (c *Context) SetContext(ctx context.Context) {
+	c.Context()
+	c.ctx = ctx
+}
+
+// setParam records a route parameter value
+func (c *Context) setParam(name, value string) {
+	if c.params == nil {
+		c.params = make(map[string]string, 2)
+	}
+	c.params[name] = value
+}
+
 // Request methods
 
 // Param gets a route parameter
@@ -48,26 +126,6 @@ func (c *Context) QueryDefault(name, defaultValue string) string {
 	return value
 }
 
-// Input gets input from request body (JSON)
-func (c *Context) Input(name string) interface{} {
-	var data map[string]interface{}
-	if err := json.Unmarshal(c.fastCtx.PostBody(), &data); err != nil {
-		return nil
-	}
-	return data[name]
-}
-
-// Bind binds request body to a struct
-func (c *Context) Bind(v interface{}) error {
-	contentType := string(c.fastCtx.Request.Header.ContentType())
-
-	if contentType == "application/json" || len(contentType) == 0 {
-		return json.Unmarshal(c.fastCtx.PostBody(), v)
-	}
-
-	return fmt.Errorf("unsupported content type: %s", contentType)
-}
-
 // FormValue gets a form value
 func (c *Context) FormValue(name string) string {
 	return string(c.fastCtx.FormValue(name))
@@ -113,13 +171,18 @@ func (c *Context) Status(code int) *Context {
 // JSON sends JSON response
 func (c *Context) JSON(data interface{}) error {
 	c.fastCtx.Response.Header.SetContentType("application/json")
+	c.fastCtx.ResetBody()
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
+	// Encode straight into the response body, without an intermediate buffer
+	if err := c.jsonCodec().NewEncoder(c.fastCtx).Encode(data); err != nil {
+		c.fastCtx.ResetBody()
 		return err
 	}
 
-	c.fastCtx.SetBody(jsonData)
+	// Encoders end each value with a newline that json.Marshal does not write
+	if body := c.fastCtx.Response.Body(); len(body) > 0 && body[len(body)-1] == '\n' {
+		c.fastCtx.Response.SwapBody(body[:len(body)-1])
+	}
 	return nil
 }
 
@@ -153,29 +216,18 @@ func (c *Context) SetHeader(key, value string) *Context {
 	return c
 }
 
-// Cookie sets a cookie
-func (c *Context) Cookie(name, value string, maxAge ...int) *Context {
-	cookie := &fasthttp.Cookie{}
-	cookie.SetKey(name)
-	cookie.SetValue(value)
-
-	if len(maxAge) > 0 {
-		cookie.SetMaxAge(maxAge[0])
-	}
-
-	c.fastCtx.Response.Header.SetCookie(cookie)
-	return c
-}
-
-// GetCookie gets a cookie value
-func (c *Context) GetCookie(name string) string {
-	return string(c.fastCtx.Request.Header.Cookie(name))
+// RouteURL builds the URL for a named route
+func (c *Context) RouteURL(name string, params ...Map) (string, error) {
+	return c.app.router.URL(name, params...)
 }
 
 // Store methods (for passing data between middleware)
 
 // Set stores a value in the context
 func (c *Context) Set(key string, value interface{}) {
+	if c.store == nil {
+		c.store = make(map[string]interface{})
+	}
 	c.store[key] = value
 }
 
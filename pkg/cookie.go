@@ -0,0 +1,317 @@
+package binigo
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+var (
+	// ErrNoCookie is returned when the request has no cookie with the given name
+	ErrNoCookie = errors.New("binigo: cookie not found")
+
+	// ErrInvalidCookie is returned when a signed or encrypted cookie fails
+	// verification, usually because it was tampered with or the key changed
+	ErrInvalidCookie = errors.New("binigo: invalid cookie")
+
+	// ErrInvalidAppKey is returned when Config.AppKey is missing or shorter
+	// than 32 bytes
+	ErrInvalidAppKey = errors.New("binigo: APP_KEY must be at least 32 bytes")
+)
+
+// SameSite is the SameSite attribute of a cookie
+type SameSite int
+
+const (
+	SameSiteDefault SameSite = iota // attribute not sent
+	SameSiteLax
+	SameSiteStrict
+	SameSiteNone // requires Secure
+)
+
+// CookieOptions holds the attributes of a cookie
+type CookieOptions struct {
+	Path     string    // defaults to "/"
+	Domain   string    // empty limits the cookie to the current host
+	MaxAge   int       // seconds; zero makes a session cookie, negative deletes it with Max-Age=0
+	Expires  time.Time // absolute expiry, sent only when MaxAge is zero
+	Secure   bool      // only send over HTTPS
+	HTTPOnly bool      // hide from JavaScript
+	SameSite SameSite
+}
+
+// DefaultCookieOptions are used when Config.Cookies is not set
+var DefaultCookieOptions = CookieOptions{
+	Path:     "/",
+	HTTPOnly: true,
+	SameSite: SameSiteLax,
+}
+
+// CookieDefaults returns the options Context.Cookie applies, from
+// Config.Cookies or DefaultCookieOptions when that is unset
+func (a *Application) CookieDefaults() CookieOptions {
+	if a.config == nil || a.config.Cookies == (CookieOptions{}) {
+		return DefaultCookieOptions
+	}
+	return a.config.Cookies
+}
+
+// cookieDefaults returns the application's cookie defaults
+func (c *Context) cookieDefaults() CookieOptions {
+	if c.app != nil {
+		return c.app.CookieDefaults()
+	}
+	return DefaultCookieOptions
+}
+
+// Cookie sets a cookie with the application's default options
+func (c *Context) Cookie(name, value string, maxAge ...int) *Context {
+	options := c.cookieDefaults()
+	if len(maxAge) > 0 {
+		options.MaxAge = maxAge[0]
+	}
+	return c.SetCookie(name, value, options)
+}
+
+// SetCookie sets a cookie with the given options. Start from
+// App().CookieDefaults() to change only some of them.
+func (c *Context) SetCookie(name, value string, options CookieOptions) *Context {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(name)
+	cookie.SetValue(value)
+
+	path := options.Path
+	if path == "" {
+		path = "/"
+	}
+	cookie.SetPath(path)
+	cookie.SetDomain(options.Domain)
+	// fasthttp sends a negative MaxAge as Max-Age=0, and omits Expires
+	// whenever Max-Age is sent
+	cookie.SetMaxAge(options.MaxAge)
+	if !options.Expires.IsZero() {
+		cookie.SetExpire(options.Expires)
+	}
+	cookie.SetSecure(options.Secure)
+	cookie.SetHTTPOnly(options.HTTPOnly)
+
+	switch options.SameSite {
+	case SameSiteLax:
+		cookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	case SameSiteStrict:
+		cookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	case SameSiteNone:
+		cookie.SetSameSite(fasthttp.CookieSameSiteNoneMode)
+	}
+
+	c.fastCtx.Response.Header.SetCookie(cookie)
+	return c
+}
+
+// ClearCookie tells the client to delete a cookie set with the default
+// path and domain
+func (c *Context) ClearCookie(name string) *Context {
+	options := c.cookieDefaults()
+	options.MaxAge = -1
+	return c.SetCookie(name, "", options)
+}
+
+// GetCookie gets a cookie value
+func (c *Context) GetCookie(name string) string {
+	return string(c.fastCtx.Request.Header.Cookie(name))
+}
+
+// SignedCookie sets a cookie whose value is readable by the client but
+// carries an HMAC-SHA256 signature made with Config.AppKey. The options
+// default to the application's cookie defaults.
+func (c *Context) SignedCookie(name, value string, options ...CookieOptions) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	signature := base64.RawURLEncoding.EncodeToString(keys.sign(name, encoded))
+	c.SetCookie(name, encoded+"."+signature, c.cookieOptions(options))
+	return nil
+}
+
+// GetSignedCookie returns the value of a cookie set by SignedCookie. It
+// returns ErrNoCookie when the cookie is missing and ErrInvalidCookie when
+// the signature does not match.
+func (c *Context) GetSignedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+
+	raw := c.GetCookie(name)
+	if raw == "" {
+		return "", ErrNoCookie
+	}
+
+	encoded, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, keys.sign(name, encoded)) {
+		return "", ErrInvalidCookie
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	return string(value), nil
+}
+
+// EncryptedCookie sets a cookie whose value is encrypted and authenticated
+// with AES-256-GCM using a key derived from Config.AppKey, so the client
+// can neither read nor change it. The options default to the
+// application's cookie defaults.
+func (c *Context) EncryptedCookie(name, value string, options ...CookieOptions) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, keys.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	// The cookie name is authenticated so a value cannot be moved to another cookie
+	sealed := keys.aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	c.SetCookie(name, base64.RawURLEncoding.EncodeToString(sealed), c.cookieOptions(options))
+	return nil
+}
+
+// GetEncryptedCookie returns the value of a cookie set by EncryptedCookie.
+// It returns ErrNoCookie when the cookie is missing and ErrInvalidCookie
+// when it cannot be decrypted.
+func (c *Context) GetEncryptedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+
+	raw := c.GetCookie(name)
+	if raw == "" {
+		return "", ErrNoCookie
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil || len(sealed) < keys.aead.NonceSize() {
+		return "", ErrInvalidCookie
+	}
+
+	nonce, ciphertext := sealed[:keys.aead.NonceSize()], sealed[keys.aead.NonceSize():]
+	value, err := keys.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	return string(value), nil
+}
+
+// cookieOptions returns the first of options, or the defaults
+func (c *Context) cookieOptions(options []CookieOptions) CookieOptions {
+	if len(options) > 0 {
+		return options[0]
+	}
+	return c.cookieDefaults()
+}
+
+// cookieKeys holds the keys derived from the application key. The AEAD is
+// safe for concurrent use, so one set serves every request.
+type cookieKeys struct {
+	signing []byte
+	aead    cipher.AEAD
+}
+
+// cookieKeys returns the keys derived from Config.AppKey, deriving them
+// on first use
+func (a *Application) cookieKeys() (*cookieKeys, error) {
+	a.cookieKeyOnce.Do(func() {
+		var appKey string
+		if a.config != nil {
+			appKey = a.config.AppKey
+		}
+		a.cookieKey, a.cookieKeyErr = newCookieKeys(appKey)
+	})
+	return a.cookieKey, a.cookieKeyErr
+}
+
+// cookieKeys returns the application's cookie keys
+func (c *Context) cookieKeys() (*cookieKeys, error) {
+	if c.app == nil {
+		return nil, ErrInvalidAppKey
+	}
+	return c.app.cookieKeys()
+}
+
+// newCookieKeys derives separate signing and encryption keys from appKey
+func newCookieKeys(appKey string) (*cookieKeys, error) {
+	key, err := decodeAppKey(appKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(deriveKey(key, "binigo cookie encryption"))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cookieKeys{
+		signing: deriveKey(key, "binigo cookie signing"),
+		aead:    aead,
+	}, nil
+}
+
+// sign returns the HMAC of a cookie's name and encoded value
+func (k *cookieKeys) sign(name, value string) []byte {
+	mac := hmac.New(sha256.New, k.signing)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// decodeAppKey accepts a raw key or a "base64:" prefixed one, as
+// generated into .env for new projects
+func decodeAppKey(appKey string) ([]byte, error) {
+	key := []byte(appKey)
+	if encoded, ok := strings.CutPrefix(appKey, "base64:"); ok {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, ErrInvalidAppKey
+		}
+		key = decoded
+	}
+
+	if len(key) < 32 {
+		return nil, ErrInvalidAppKey
+	}
+	return key, nil
+}
+
+// deriveKey derives a 32-byte key for one purpose from the application key
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,44 @@
+package binigo_test
+
+import (
+	"strings"
+	"testing"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+)
+
+func TestCookieMaxAge(t *testing.T) {
+	app := binigo.NewApplication(nil)
+	app.Get("/set", func(ctx *binigo.Context) error {
+		ctx.Cookie("session", "abc", 60)
+		ctx.Cookie("pref", "dark")
+		return nil
+	})
+	app.Get("/delete", func(ctx *binigo.Context) error {
+		ctx.Cookie("session", "", -1)
+		ctx.ClearCookie("pref")
+		return nil
+	})
+
+	ta := binigotest.New(t, app)
+
+	set := ta.Test().Get("/set").Expect(200).Raw()
+	session := string(set.Header.PeekCookie("session"))
+	if !strings.Contains(session, "max-age=60") || !strings.Contains(session, "SameSite=Lax") {
+		t.Fatalf("expected max-age and the default attributes, got %q", session)
+	}
+	if pref := string(set.Header.PeekCookie("pref")); strings.Contains(pref, "max-age") || strings.Contains(pref, "expires") {
+		t.Fatalf("expected a session cookie, got %q", pref)
+	}
+
+	deleted := ta.Test().Get("/delete").Expect(200).Raw()
+	for _, name := range []string{"session", "pref"} {
+		if cookie := string(deleted.Header.PeekCookie(name)); !strings.Contains(cookie, "max-age=0") {
+			t.Errorf("expected %s to be expired, got %q", name, cookie)
+		}
+		if _, ok := ta.Jar().Get(name); ok {
+			t.Errorf("expected %s to be deleted from the jar", name)
+		}
+	}
+}
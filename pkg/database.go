@@ -1,9 +1,9 @@
 package binigo
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"reflect"
 	"strings"
 	"time"
 
@@ -14,6 +14,7 @@ import (
 type DB struct {
 	conn   *sql.DB
 	config DatabaseConfig
+	ctx    context.Context
 }
 
 // NewDB creates a new database connection
@@ -37,6 +38,22 @@ func NewDB(config DatabaseConfig) (*DB, error) {
 	}, nil
 }
 
+// WithContext returns a copy of db whose queries use ctx, so they are
+// cancelled with the request or when its deadline expires
+func (db *DB) WithContext(ctx context.Context) *DB {
+	clone := *db
+	clone.ctx = ctx
+	return &clone
+}
+
+// context returns the context queries run under
+func (db *DB) context() context.Context {
+	if db.ctx != nil {
+		return db.ctx
+	}
+	return context.Background()
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	return db.conn.Close()
@@ -52,6 +69,7 @@ type Model struct {
 // QueryBuilder provides a fluent interface for building queries
 type QueryBuilder struct {
 	db          *DB
+	ctx         context.Context
 	table       string
 	selectCols  []string
 	whereClause []string
@@ -66,11 +84,18 @@ type QueryBuilder struct {
 func (db *DB) Table(table string) *QueryBuilder {
 	return &QueryBuilder{
 		db:         db,
+		ctx:        db.context(),
 		table:      table,
 		selectCols: []string{"*"},
 	}
 }
 
+// WithContext sets the context the query runs under
+func (qb *QueryBuilder) WithContext(ctx context.Context) *QueryBuilder {
+	qb.ctx = ctx
+	return qb
+}
+
 // Select specifies columns to select
 func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
 	qb.selectCols = columns
@@ -184,7 +209,7 @@ func (qb *QueryBuilder) buildQuery() string {
 func (qb *QueryBuilder) Get(dest interface{}) error {
 	query := qb.buildQuery()
 
-	rows, err := qb.db.conn.Query(query, qb.whereArgs...)
+	rows, err := qb.db.conn.QueryContext(qb.ctx, query, qb.whereArgs...)
 	if err != nil {
 		return err
 	}
@@ -193,13 +218,19 @@ func (qb *QueryBuilder) Get(dest interface{}) error {
 	return scanRows(rows, dest)
 }
 
-// First executes the query and returns the first row
+// First executes the query and returns the first row.
+// It returns sql.ErrNoRows when nothing matches.
 func (qb *QueryBuilder) First(dest interface{}) error {
 	qb.Limit(1)
 	query := qb.buildQuery()
 
-	row := qb.db.conn.QueryRow(query, qb.whereArgs...)
-	return scanRow(row, dest)
+	rows, err := qb.db.conn.QueryContext(qb.ctx, query, qb.whereArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanOne(rows, dest)
 }
 
 // Count returns the count of rows
@@ -216,7 +247,7 @@ func (qb *QueryBuilder) Count() (int64, error) {
 	}
 
 	var count int64
-	err := qb.db.conn.QueryRow(query, qb.whereArgs...).Scan(&count)
+	err := qb.db.conn.QueryRowContext(qb.ctx, query, qb.whereArgs...).Scan(&count)
 	return count, err
 }
 
@@ -240,7 +271,7 @@ func (qb *QueryBuilder) Insert(data Map) (int64, error) {
 		strings.Join(placeholders, ", "))
 
 	var id int64
-	err := qb.db.conn.QueryRow(query, values...).Scan(&id)
+	err := qb.db.conn.QueryRowContext(qb.ctx, query, values...).Scan(&id)
 	return id, err
 }
 
@@ -269,7 +300,7 @@ func (qb *QueryBuilder) Update(data Map) (int64, error) {
 		values = append(values, qb.whereArgs...)
 	}
 
-	result, err := qb.db.conn.Exec(query, values...)
+	result, err := qb.db.conn.ExecContext(qb.ctx, query, values...)
 	if err != nil {
 		return 0, err
 	}
@@ -289,7 +320,7 @@ func (qb *QueryBuilder) Delete() (int64, error) {
 		query += " WHERE " + whereStr
 	}
 
-	result, err := qb.db.conn.Exec(query, qb.whereArgs...)
+	result, err := qb.db.conn.ExecContext(qb.ctx, query, qb.whereArgs...)
 	if err != nil {
 		return 0, err
 	}
@@ -299,17 +330,17 @@ func (qb *QueryBuilder) Delete() (int64, error) {
 
 // Raw executes a raw SQL query
 func (db *DB) Raw(query string, args ...interface{}) (*sql.Rows, error) {
-	return db.conn.Query(query, args...)
+	return db.conn.QueryContext(db.context(), query, args...)
 }
 
 // Exec executes a query without returning rows
 func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return db.conn.Exec(query, args...)
+	return db.conn.ExecContext(db.context(), query, args...)
 }
 
 // Transaction begins a transaction
 func (db *DB) Transaction(fn func(*sql.Tx) error) error {
-	tx, err := db.conn.Begin()
+	tx, err := db.conn.BeginTx(db.context(), nil)
 	if err != nil {
 		return err
 	}
@@ -328,37 +359,3 @@ func (db *DB) Transaction(fn func(*sql.Tx) error) error {
 
 	return tx.Commit()
 }
-
-// Helper functions for scanning
-
-func scanRows(rows *sql.Rows, dest interface{}) error {
-	destValue := reflect.ValueOf(dest)
-	if destValue.Kind() != reflect.Ptr {
-		return fmt.Errorf("dest must be a pointer")
-	}
-
-	sliceValue := destValue.Elem()
-	if sliceValue.Kind() != reflect.Slice {
-		return fmt.Errorf("dest must be a pointer to a slice")
-	}
-
-	elemType := sliceValue.Type().Elem()
-
-	for rows.Next() {
-		elem := reflect.New(elemType).Elem()
-
-		if err := scanRow(rows, elem.Addr().Interface()); err != nil {
-			return err
-		}
-
-		sliceValue.Set(reflect.Append(sliceValue, elem))
-	}
-
-	return rows.Err()
-}
-
-func scanRow(scanner interface{}, dest interface{}) error {
-	// Simplified scan - in production, use a proper struct scanner
-	// like sqlx or implement full reflection-based scanning
-	return fmt.Errorf("implement full struct scanning based on db tags")
-}
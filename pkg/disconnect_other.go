@@ -0,0 +1,14 @@
+//go:build !unix
+
+package binigo
+
+import (
+	"context"
+	"net"
+)
+
+// watchDisconnect is not supported on this platform; the request context
+// is still cancelled by route timeouts and when the request finishes.
+func watchDisconnect(conn net.Conn, cancel context.CancelFunc) (stop func()) {
+	return func() {}
+}
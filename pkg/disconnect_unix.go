@@ -0,0 +1,57 @@
+//go:build unix
+
+package binigo
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// watchDisconnect cancels the request context when the client closes the
+// connection. It waits for the socket to become readable and peeks at it:
+// EOF or a reset means the client is gone, while pipelined request data
+// ends the watch without consuming anything. The returned function stops
+// the watch and must be called before the server reads from conn again.
+func watchDisconnect(conn net.Conn, cancel context.CancelFunc) (stop func()) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return func() {}
+	}
+
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		buf := make([]byte, 1)
+		_ = raw.Read(func(fd uintptr) bool {
+			n, _, err := syscall.Recvfrom(int(fd), buf, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+			if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EINTR) {
+				return false
+			}
+			if err != nil || n == 0 {
+				cancel()
+			}
+			return true
+		})
+	}()
+
+	return func() {
+		// Wake the pending read, then clear the deadline for the server
+		_ = conn.SetReadDeadline(time.Unix(1, 0))
+		<-done
+		_ = conn.SetReadDeadline(time.Time{})
+	}
+}
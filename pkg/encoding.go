@@ -0,0 +1,332 @@
+package binigo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The YAML and MessagePack encoders work from a value's JSON
+// representation as produced by the application's JSONCodec, so json
+// struct tags and MarshalJSON methods apply to every format. Decoding
+// keeps object keys in their original order.
+
+// orderedMap is a decoded JSON object that keeps its key order
+type orderedMap []orderedField
+
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+// toOrderedTree converts v into orderedMap, []interface{}, json.Number,
+// string, bool and nil values
+func toOrderedTree(codec JSONCodec, v interface{}) (interface{}, error) {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return readOrderedNode(dec)
+}
+
+func readOrderedNode(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := orderedMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := readOrderedNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, orderedField{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return object, err
+	case '[':
+		array := []interface{}{}
+		for dec.More() {
+			value, err := readOrderedNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = dec.Token()
+		return array, err
+	}
+
+	return nil, fmt.Errorf("unexpected JSON delimiter %q", delim)
+}
+
+// marshalYAML encodes v as a YAML document in block style
+func marshalYAML(codec JSONCodec, v interface{}) ([]byte, error) {
+	tree, err := toOrderedTree(codec, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	switch node := tree.(type) {
+	case orderedMap:
+		if len(node) > 0 {
+			writeYAMLMap(&b, node, 0, false)
+			return b.Bytes(), nil
+		}
+	case []interface{}:
+		if len(node) > 0 {
+			writeYAMLList(&b, node, 0)
+			return b.Bytes(), nil
+		}
+	}
+
+	b.WriteString(yamlScalar(tree) + "\n")
+	return b.Bytes(), nil
+}
+
+// writeYAMLMap writes the fields of m at indent. When inline is true the
+// first field continues the current line, as after a list dash.
+func writeYAMLMap(b *bytes.Buffer, m orderedMap, indent int, inline bool) {
+	for i, field := range m {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat(" ", indent))
+		}
+		b.WriteString(yamlString(field.key) + ":")
+		writeYAMLValue(b, field.value, indent)
+	}
+}
+
+func writeYAMLList(b *bytes.Buffer, list []interface{}, indent int) {
+	for _, item := range list {
+		b.WriteString(strings.Repeat(" ", indent) + "-")
+
+		switch node := item.(type) {
+		case orderedMap:
+			if len(node) > 0 {
+				b.WriteString(" ")
+				writeYAMLMap(b, node, indent+2, true)
+				continue
+			}
+		case []interface{}:
+			if len(node) > 0 {
+				b.WriteString("\n")
+				writeYAMLList(b, node, indent+2)
+				continue
+			}
+		}
+
+		b.WriteString(" " + yamlScalar(item) + "\n")
+	}
+}
+
+// writeYAMLValue writes the value of a mapping key, nesting collections
+// on the following lines
+func writeYAMLValue(b *bytes.Buffer, value interface{}, indent int) {
+	switch node := value.(type) {
+	case orderedMap:
+		if len(node) > 0 {
+			b.WriteString("\n")
+			writeYAMLMap(b, node, indent+2, false)
+			return
+		}
+	case []interface{}:
+		if len(node) > 0 {
+			b.WriteString("\n")
+			writeYAMLList(b, node, indent+2)
+			return
+		}
+	}
+
+	b.WriteString(" " + yamlScalar(value) + "\n")
+}
+
+func yamlScalar(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	case orderedMap:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return yamlString(fmt.Sprint(value))
+}
+
+// yamlReserved are plain scalars that YAML parsers read as something other
+// than a string
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true, ".inf": true, "-.inf": true, ".nan": true,
+}
+
+// yamlString writes s as a plain scalar when that reads back as the same
+// string, and double-quoted otherwise
+func yamlString(s string) string {
+	if s == "" || s != strings.TrimSpace(s) || yamlReserved[strings.ToLower(s)] ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`.+0123456789") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+
+	for _, r := range s {
+		if !unicode.IsPrint(r) || r == unicode.ReplacementChar {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// marshalMsgPack encodes v in the MessagePack format
+func marshalMsgPack(codec JSONCodec, v interface{}) ([]byte, error) {
+	tree, err := toOrderedTree(codec, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := writeMsgPack(&b, tree); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeMsgPack(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		b.WriteByte(0xc0)
+	case bool:
+		if v {
+			b.WriteByte(0xc3)
+		} else {
+			b.WriteByte(0xc2)
+		}
+	case json.Number:
+		return writeMsgPackNumber(b, v)
+	case string:
+		writeMsgPackHeader(b, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		b.WriteString(v)
+	case []interface{}:
+		writeMsgPackHeader(b, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMsgPack(b, item); err != nil {
+				return err
+			}
+		}
+	case orderedMap:
+		writeMsgPackHeader(b, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, field := range v {
+			writeMsgPackHeader(b, len(field.key), 0xa0, 32, 0xd9, 0xda, 0xdb)
+			b.WriteString(field.key)
+			if err := writeMsgPack(b, field.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported value %T", value)
+	}
+	return nil
+}
+
+// writeMsgPackHeader writes a length-prefixed type header, using the fix
+// form below fixLimit and the 8, 16 or 32-bit form otherwise. A zero
+// code8 means the type has no 8-bit form.
+func writeMsgPackHeader(b *bytes.Buffer, n int, fix byte, fixLimit int, code8, code16, code32 byte) {
+	switch {
+	case n < fixLimit:
+		b.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		b.WriteByte(code8)
+		b.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		b.WriteByte(code16)
+		b.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		b.WriteByte(code32)
+		b.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// writeMsgPackNumber writes integers in their smallest form and anything
+// else as a float64
+func writeMsgPackNumber(b *bytes.Buffer, n json.Number) error {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		switch {
+		case i >= 0 && i <= math.MaxInt8:
+			b.WriteByte(byte(i))
+		case i < 0 && i >= -32:
+			b.WriteByte(byte(int8(i)))
+		case i >= 0:
+			writeMsgPackUint(b, uint64(i))
+		case i >= math.MinInt8:
+			b.WriteByte(0xd0)
+			b.WriteByte(byte(int8(i)))
+		case i >= math.MinInt16:
+			b.WriteByte(0xd1)
+			b.Write(binary.BigEndian.AppendUint16(nil, uint16(int16(i))))
+		case i >= math.MinInt32:
+			b.WriteByte(0xd2)
+			b.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(i))))
+		default:
+			b.WriteByte(0xd3)
+			b.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		}
+		return nil
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		writeMsgPackUint(b, u)
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	b.WriteByte(0xcb)
+	b.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return nil
+}
+
+func writeMsgPackUint(b *bytes.Buffer, u uint64) {
+	switch {
+	case u <= math.MaxUint8:
+		b.WriteByte(0xcc)
+		b.WriteByte(byte(u))
+	case u <= math.MaxUint16:
+		b.WriteByte(0xcd)
+		b.Write(binary.BigEndian.AppendUint16(nil, uint16(u)))
+	case u <= math.MaxUint32:
+		b.WriteByte(0xce)
+		b.Write(binary.BigEndian.AppendUint32(nil, uint32(u)))
+	default:
+		b.WriteByte(0xcf)
+		b.Write(binary.BigEndian.AppendUint64(nil, u))
+	}
+}
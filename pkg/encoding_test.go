@@ -0,0 +1,211 @@
+package binigo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// unhex decodes space-separated hex bytes
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestMarshalMsgPack(t *testing.T) {
+	str := func(n int) string { return strings.Repeat("a", n) }
+
+	list16 := make([]int, 16)
+	map16 := Map{}
+	var map16Want []byte
+	for i := 0; i < 16; i++ {
+		key := fmt.Sprintf("k%02d", i)
+		map16[key] = i
+		map16Want = append(map16Want, 0xa3)
+		map16Want = append(map16Want, key...)
+		map16Want = append(map16Want, byte(i))
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []byte
+	}{
+		{"nil", nil, unhex("c0")},
+		{"true", true, unhex("c3")},
+		{"false", false, unhex("c2")},
+
+		{"positive fixint", 0, unhex("00")},
+		{"largest positive fixint", 127, unhex("7f")},
+		{"negative fixint", -1, unhex("ff")},
+		{"smallest negative fixint", -32, unhex("e0")},
+		{"int8", -33, unhex("d0 df")},
+		{"smallest int8", math.MinInt8, unhex("d0 80")},
+		{"int16", math.MinInt8 - 1, unhex("d1 ff 7f")},
+		{"smallest int16", math.MinInt16, unhex("d1 80 00")},
+		{"int32", math.MinInt16 - 1, unhex("d2 ff ff 7f ff")},
+		{"int64", math.MinInt32 - 1, unhex("d3 ff ff ff ff 7f ff ff ff")},
+		{"uint8", 128, unhex("cc 80")},
+		{"largest uint8", math.MaxUint8, unhex("cc ff")},
+		{"uint16", math.MaxUint8 + 1, unhex("cd 01 00")},
+		{"uint32", math.MaxUint16 + 1, unhex("ce 00 01 00 00")},
+		{"uint64", math.MaxUint32 + 1, unhex("cf 00 00 00 01 00 00 00 00")},
+		{"largest uint64", uint64(math.MaxUint64), unhex("cf ff ff ff ff ff ff ff ff")},
+		{"float64", 1.5, unhex("cb 3f f8 00 00 00 00 00 00")},
+		{"negative float64", -0.25, unhex("cb bf d0 00 00 00 00 00 00")},
+
+		{"empty string", "", unhex("a0")},
+		{"fixstr", "abc", unhex("a3 61 62 63")},
+		{"largest fixstr", str(31), append(unhex("bf"), str(31)...)},
+		{"str8", str(32), append(unhex("d9 20"), str(32)...)},
+		{"largest str8", str(255), append(unhex("d9 ff"), str(255)...)},
+		{"str16", str(256), append(unhex("da 01 00"), str(256)...)},
+		{"str32", str(65536), append(unhex("db 00 01 00 00"), str(65536)...)},
+
+		{"empty array", []int{}, unhex("90")},
+		{"fixarray", []interface{}{1, "a", nil}, unhex("93 01 a1 61 c0")},
+		{"array16", list16, append(unhex("dc 00 10"), make([]byte, 16)...)},
+		{"empty map", Map{}, unhex("80")},
+		{"fixmap", Map{"a": 1}, unhex("81 a1 61 01")},
+		{"map16", map16, append(unhex("de 00 10"), map16Want...)},
+		{"struct keeps field order", struct {
+			B bool   `json:"b"`
+			A []int  `json:"a"`
+			C string `json:"c,omitempty"`
+		}{true, []int{1}, ""}, unhex("82 a1 62 c3 a1 61 91 01")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalMsgPack(StdJSONCodec{}, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				if len(got) > 32 || len(tt.want) > 32 {
+					t.Fatalf("expected %d bytes starting % x, got %d bytes starting % x",
+						len(tt.want), tt.want[:min(8, len(tt.want))], len(got), got[:min(8, len(got))])
+				}
+				t.Fatalf("expected % x, got % x", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	for s, want := range map[string]string{
+		"hello world":  "hello world",
+		"Ada":          "Ada",
+		"a-b:c":        "a-b:c",
+		"":             `""`,
+		"true":         `"true"`,
+		"False":        `"False"`,
+		"yes":          `"yes"`,
+		"NO":           `"NO"`,
+		"on":           `"on"`,
+		"y":            `"y"`,
+		"null":         `"null"`,
+		"~":            `"~"`,
+		".inf":         `".inf"`,
+		"12":           `"12"`,
+		"-1":           `"-1"`,
+		"+1":           `"+1"`,
+		"1.5":          `"1.5"`,
+		".5":           `".5"`,
+		"0x1f":         `"0x1f"`,
+		" padded":      `" padded"`,
+		"trailing ":    `"trailing "`,
+		"key: value":   `"key: value"`,
+		"label:":       `"label:"`,
+		"a #comment":   `"a #comment"`,
+		"#tag":         `"#tag"`,
+		"- item":       `"- item"`,
+		"*alias":       `"*alias"`,
+		"'quoted'":     `"'quoted'"`,
+		"line1\nline2": `"line1\nline2"`,
+		"tab\there":    `"tab\there"`,
+	} {
+		if got := yamlString(s); got != want {
+			t.Errorf("yamlString(%q): expected %s, got %s", s, want, got)
+		}
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	type user struct {
+		Name  string   `json:"name"`
+		Admin bool     `json:"admin"`
+		Tags  []string `json:"tags"`
+		Notes *string  `json:"notes"`
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"scalar", 42, "42\n"},
+		{"string scalar", "yes", "\"yes\"\n"},
+		{"null", nil, "null\n"},
+		{"empty map", Map{}, "{}\n"},
+		{"empty slice", []string{}, "[]\n"},
+		{
+			"struct keeps field order",
+			user{Name: "Ada", Admin: true, Tags: []string{"math", "1843"}},
+			"name: Ada\nadmin: true\ntags:\n  - math\n  - \"1843\"\nnotes: null\n",
+		},
+		{
+			"empty collections as values",
+			Map{"list": []int{}, "map": Map{}, "nil": []int(nil)},
+			"list: []\nmap: {}\nnil: null\n",
+		},
+		{
+			"nested maps",
+			Map{"user": Map{"name": "Ada", "address": Map{"city": "London"}}},
+			"user:\n  address:\n    city: London\n  name: Ada\n",
+		},
+		{
+			"nested sequences",
+			[][]int{{1, 2}, {}, {3}},
+			"-\n  - 1\n  - 2\n- []\n-\n  - 3\n",
+		},
+		{
+			"sequence of maps",
+			[]Map{{"id": 1, "tags": []string{"a"}}, {}},
+			"- id: 1\n  tags:\n    - a\n- {}\n",
+		},
+		{
+			"multi-line strings",
+			Map{"bio": "line one\nline two", "quote": `say "hi"`},
+			"bio: \"line one\\nline two\"\nquote: say \"hi\"\n",
+		},
+		{
+			"reserved keys",
+			Map{"true": 1, "2": "two"},
+			"\"2\": two\n\"true\": 1\n",
+		},
+		{
+			"numbers",
+			Map{"int": 7, "float": 2.5, "negative": -3, "big": 1e21},
+			"big: 1e+21\nfloat: 2.5\nint: 7\nnegative: -3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := marshalYAML(StdJSONCodec{}, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("expected\n%s\ngot\n%s", tt.want, got)
+			}
+		})
+	}
+}
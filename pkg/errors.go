@@ -0,0 +1,143 @@
+package binigo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// HTTPError is an error that carries the HTTP response it should produce
+type HTTPError struct {
+	Status  int         // HTTP status code
+	Code    string      // machine-readable error code, e.g. "not_found"
+	Message string      // message safe to show to clients
+	Details interface{} // optional extra data such as field errors
+	Err     error       // wrapped cause, only exposed in debug mode
+	stack   []byte
+}
+
+// NewHTTPError creates an HTTP error. The message defaults to the
+// standard status text.
+func NewHTTPError(status int, message ...string) *HTTPError {
+	e := &HTTPError{
+		Status:  status,
+		Message: fasthttp.StatusMessage(status),
+	}
+
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+
+	return e
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Status, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Status, e.Message)
+}
+
+// Unwrap returns the wrapped cause
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode sets the machine-readable error code
+func (e *HTTPError) WithCode(code string) *HTTPError {
+	e.Code = code
+	return e
+}
+
+// WithDetails attaches extra data to the error response
+func (e *HTTPError) WithDetails(details interface{}) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// Wrap records the underlying cause of the error
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+// ErrorHandlerFunc turns an error returned from the handler chain into a response
+type ErrorHandlerFunc func(ctx *Context, err error)
+
+// DefaultErrorHandler renders errors as JSON. Details of unexpected errors
+// and stack traces are only included when Config.Debug is true.
+func DefaultErrorHandler(ctx *Context, err error) {
+	httpErr := ToHTTPError(err)
+
+	// Validation failures keep the message and errors keys clients already read
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		if jsonErr := ctx.Status(httpErr.Status).JSON(Map{
+			"success": false,
+			"message": httpErr.Message,
+			"code":    httpErr.Code,
+			"errors":  validationErr.Errors,
+		}); jsonErr != nil {
+			ctx.fastCtx.Error(fasthttp.StatusMessage(httpErr.Status), httpErr.Status)
+		}
+		return
+	}
+
+	response := Map{
+		"success": false,
+		"error":   httpErr.Message,
+	}
+
+	if httpErr.Code != "" {
+		response["code"] = httpErr.Code
+	}
+
+	if httpErr.Details != nil {
+		response["details"] = httpErr.Details
+	}
+
+	if ctx.app != nil && ctx.app.config != nil && ctx.app.config.Debug {
+		debugInfo := Map{"error": err.Error()}
+		if httpErr.stack != nil {
+			debugInfo["stack"] = strings.Split(strings.TrimSpace(string(httpErr.stack)), "\n")
+		}
+		response["debug"] = debugInfo
+	}
+
+	if jsonErr := ctx.Status(httpErr.Status).JSON(response); jsonErr != nil {
+		ctx.fastCtx.Error(fasthttp.StatusMessage(httpErr.Status), httpErr.Status)
+	}
+}
+
+// ToHTTPError converts any error to an HTTPError: HTTP errors are returned
+// as is, sql.ErrNoRows becomes 404, validation failures become 422, an
+// expired request deadline becomes 503 and everything else becomes 500
+func ToHTTPError(err error) *HTTPError {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return NewHTTPError(fasthttp.StatusUnprocessableEntity, "Validation failed").
+			WithCode("validation_failed").
+			WithDetails(validationErr.Errors).
+			Wrap(err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewHTTPError(fasthttp.StatusServiceUnavailable, "Request timed out").WithCode("timeout").Wrap(err)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found").Wrap(err)
+	}
+
+	return NewHTTPError(fasthttp.StatusInternalServerError).WithCode("internal_error").Wrap(err)
+}
@@ -0,0 +1,319 @@
+package binigo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// SendFile sends the file at path with its content type, Last-Modified and
+// ETag headers. Conditional requests (If-None-Match, If-Modified-Since) are
+// answered with 304 and single byte ranges with 206. Paths containing ".."
+// segments are rejected; use SafePath when the name comes from the request.
+func (c *Context) SendFile(path string) error {
+	file, info, err := openFile(path)
+	if err != nil {
+		return err
+	}
+
+	return c.serveContent(file, info, mimeType(path))
+}
+
+// Download sends the file at path as an attachment named filename, which
+// defaults to the file's base name
+func (c *Context) Download(path string, filename ...string) error {
+	file, info, err := openFile(path)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(path)
+	if len(filename) > 0 && filename[0] != "" {
+		name = filename[0]
+	}
+	c.SetHeader("Content-Disposition", contentDisposition("attachment", name))
+
+	return c.serveContent(file, info, mimeType(name))
+}
+
+// Stream sends everything read from r with the given content type. The
+// body is sent chunked; r is closed afterwards if it implements io.Closer.
+func (c *Context) Stream(r io.Reader, contentType string) error {
+	c.fastCtx.SetContentType(contentType)
+	c.fastCtx.SetBodyStream(r, -1)
+	return nil
+}
+
+// SafePath joins name onto root and returns an error if the result would
+// escape root, including through symlinks. The returned path has its
+// symlinks resolved, so opening it reads the file that was checked even if
+// a link is changed afterwards. Use it for file names taken from route
+// parameters or the query string:
+//
+//	path, err := binigo.SafePath("storage/uploads", ctx.Param("name"))
+func SafePath(root, name string) (string, error) {
+	if strings.ContainsRune(name, 0) {
+		return "", NewHTTPError(fasthttp.StatusBadRequest, "Invalid file path").WithCode("invalid_path")
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+
+	// Joining onto "/" resolves every ".." within the name itself
+	path := filepath.Join(absRoot, filepath.FromSlash(filepath.Clean("/"+name)))
+
+	// Resolve symlinks so a link inside root cannot point outside it
+	resolvedRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found").Wrap(err)
+		}
+		return "", err
+	}
+
+	if rel, err := filepath.Rel(resolvedRoot, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found")
+	}
+
+	return resolved, nil
+}
+
+// openFile opens a regular file, mapping failures to HTTP errors
+func openFile(path string) (*os.File, os.FileInfo, error) {
+	if hasDotDotSegment(path) || strings.ContainsRune(path, 0) {
+		return nil, nil, NewHTTPError(fasthttp.StatusBadRequest, "Invalid file path").WithCode("invalid_path")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fileError(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fileError(err)
+	}
+
+	if info.IsDir() {
+		file.Close()
+		return nil, nil, NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found")
+	}
+
+	return file, info, nil
+}
+
+// fileError converts an os error into an HTTP error
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found").Wrap(err)
+	case errors.Is(err, fs.ErrPermission):
+		return NewHTTPError(fasthttp.StatusForbidden).WithCode("forbidden").Wrap(err)
+	default:
+		return err
+	}
+}
+
+// hasDotDotSegment reports whether any slash-separated segment of path is ".."
+func hasDotDotSegment(path string) bool {
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if segment == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// serveContent writes file honouring conditional and range headers. It
+// takes ownership of file.
+func (c *Context) serveContent(file *os.File, info os.FileInfo, contentType string) error {
+	size := info.Size()
+	modTime := info.ModTime().UTC().Truncate(time.Second)
+	etag := fmt.Sprintf(`"%x-%x"`, size, info.ModTime().UnixNano())
+
+	header := &c.fastCtx.Response.Header
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", modTime.Format(httpTimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+	c.fastCtx.SetContentType(contentType)
+
+	if c.notModified(etag, modTime) {
+		file.Close()
+		header.Del("Content-Type")
+		c.fastCtx.SetStatusCode(fasthttp.StatusNotModified)
+		return nil
+	}
+
+	rangeHeader := c.Header("Range")
+	if rangeHeader == "" || !c.rangeApplies(etag, modTime) {
+		c.fastCtx.SetStatusCode(fasthttp.StatusOK)
+		c.fastCtx.SetBodyStream(file, int(size))
+		return nil
+	}
+
+	start, end, ok := parseByteRange(rangeHeader, size)
+	if !ok {
+		file.Close()
+		header.Set("Content-Range", "bytes */"+strconv.FormatInt(size, 10))
+		return NewHTTPError(fasthttp.StatusRequestedRangeNotSatisfiable).WithCode("range_not_satisfiable")
+	}
+	if start < 0 {
+		// Multiple ranges are not supported; send the whole file instead
+		c.fastCtx.SetStatusCode(fasthttp.StatusOK)
+		c.fastCtx.SetBodyStream(file, int(size))
+		return nil
+	}
+
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	length := end - start + 1
+	header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	c.fastCtx.SetStatusCode(fasthttp.StatusPartialContent)
+	c.fastCtx.SetBodyStream(limitedFile{io.LimitReader(file, length), file}, int(length))
+	return nil
+}
+
+// httpTimeFormat is the time format used in HTTP date headers
+const httpTimeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+// limitedFile reads part of a file and closes the file when done
+type limitedFile struct {
+	io.Reader
+	io.Closer
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tags were sent
+func (c *Context) notModified(etag string, modTime time.Time) bool {
+	method := c.Method()
+	if method != fasthttp.MethodGet && method != fasthttp.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := c.Header("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	if since := c.Header("If-Modified-Since"); since != "" {
+		if t, err := fasthttp.ParseHTTPDate([]byte(since)); err == nil {
+			return !modTime.After(t)
+		}
+	}
+
+	return false
+}
+
+// rangeApplies checks If-Range: the range is only honoured when the
+// validator still matches the current file
+func (c *Context) rangeApplies(etag string, modTime time.Time) bool {
+	if c.Method() != fasthttp.MethodGet {
+		return false
+	}
+
+	ifRange := c.Header("If-Range")
+	if ifRange == "" {
+		return true
+	}
+	if strings.HasPrefix(ifRange, `"`) {
+		return ifRange == etag
+	}
+	if t, err := fasthttp.ParseHTTPDate([]byte(ifRange)); err == nil {
+		return modTime.Equal(t)
+	}
+	return false
+}
+
+// etagMatches compares an If-None-Match list against etag using weak comparison
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseByteRange parses a Range header for a file of size bytes. It returns
+// start -1 for multi-range requests and ok false when the range cannot be
+// satisfied.
+func parseByteRange(header string, size int64) (start, end int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found {
+		return -1, -1, true
+	}
+	if strings.Contains(spec, ",") {
+		return -1, -1, true
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	switch {
+	case first == "":
+		// Suffix range: the last N bytes
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return 0, 0, false
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, size - 1, true
+	default:
+		s, err := strconv.ParseInt(first, 10, 64)
+		if err != nil || s < 0 || s >= size {
+			return 0, 0, false
+		}
+		e := size - 1
+		if last != "" {
+			if e, err = strconv.ParseInt(last, 10, 64); err != nil || e < s {
+				return 0, 0, false
+			}
+			if e >= size {
+				e = size - 1
+			}
+		}
+		return s, e, true
+	}
+}
+
+// mimeType returns the content type for a file name
+func mimeType(name string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// contentDisposition formats a Content-Disposition header, encoding
+// non-ASCII file names as RFC 2231 requires
+func contentDisposition(disposition, filename string) string {
+	if value := mime.FormatMediaType(disposition, map[string]string{"filename": filename}); value != "" {
+		return value
+	}
+	return disposition
+}
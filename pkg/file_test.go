@@ -0,0 +1,60 @@
+package binigo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafePath(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"root/docs/guide.txt", "secret.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(root, "docs", "guide.txt"), filepath.Join(root, "latest.txt")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(root, "escape.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guide := filepath.Join(resolvedRoot, "docs", "guide.txt")
+
+	tests := []struct {
+		name   string
+		want   string
+		status int
+	}{
+		{name: "docs/guide.txt", want: guide},
+		{name: "/docs/../docs/guide.txt", want: guide},
+		{name: "../secret.txt", status: 404},
+		{name: "../../secret.txt", status: 404},
+		{name: "latest.txt", want: guide},
+		{name: "escape.txt", status: 404},
+		{name: "missing.txt", status: 404},
+		{name: "docs/guide.txt\x00.png", status: 400},
+	}
+
+	for _, tt := range tests {
+		got, err := SafePath(root, tt.name)
+		if tt.status != 0 {
+			if err == nil || ToHTTPError(err).Status != tt.status {
+				t.Errorf("%q: expected status %d, got %q, %v", tt.name, tt.status, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %q, got %q, %v", tt.name, tt.want, got, err)
+		}
+	}
+}
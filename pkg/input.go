@@ -0,0 +1,158 @@
+package binigo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// inputData parses the JSON body on first use and caches the result. An
+// empty body yields an empty map.
+func (c *Context) inputData() (Map, error) {
+	if !c.inputParsed {
+		c.inputParsed = true
+		c.input = Map{}
+
+		if body := c.fastCtx.PostBody(); len(body) > 0 {
+			if err := c.jsonCodec().Unmarshal(body, &c.input); err != nil {
+				c.input = Map{}
+				c.inputErr = err
+			}
+		}
+	}
+
+	return c.input, c.inputErr
+}
+
+// All returns every field of the JSON body. It returns an empty map when
+// the body is missing or is not a JSON object.
+func (c *Context) All() Map {
+	data, _ := c.inputData()
+	return data
+}
+
+// Input gets a value from the JSON body. Nested values are addressed with
+// dots, and array elements by index: Input("address.city"), Input("items.0.id").
+func (c *Context) Input(name string) interface{} {
+	value, _ := lookupInput(c.All(), name)
+	return value
+}
+
+// HasInput reports whether the JSON body contains name
+func (c *Context) HasInput(name string) bool {
+	_, ok := lookupInput(c.All(), name)
+	return ok
+}
+
+// InputString gets a string from the JSON body. Numbers and booleans are
+// formatted; missing values return the default or "".
+func (c *Context) InputString(name string, defaultValue ...string) string {
+	switch value := c.Input(name).(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return ""
+}
+
+// InputInt gets an integer from the JSON body, accepting numbers and
+// numeric strings. Missing or invalid values return the default or 0.
+func (c *Context) InputInt(name string, defaultValue ...int) int {
+	switch value := c.Input(name).(type) {
+	case float64:
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return 0
+}
+
+// InputBool gets a boolean from the JSON body, accepting booleans, 0/1 and
+// strings such as "true", "on" or "yes". Missing or invalid values return
+// the default or false.
+func (c *Context) InputBool(name string, defaultValue ...bool) bool {
+	switch value := c.Input(name).(type) {
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		if parsed, err := parseBool(strings.TrimSpace(value)); err == nil {
+			return parsed
+		}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return false
+}
+
+// Only returns the given fields of the JSON body, skipping missing ones.
+// Dotted names are looked up as paths and keep their dotted key.
+func (c *Context) Only(names ...string) Map {
+	data := c.All()
+	result := make(Map, len(names))
+
+	for _, name := range names {
+		if value, ok := lookupInput(data, name); ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// Except returns the top-level fields of the JSON body other than names
+func (c *Context) Except(names ...string) Map {
+	data := c.All()
+	result := make(Map, len(data))
+
+	for key, value := range data {
+		if !containsString(names, key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// lookupInput walks decoded JSON along a dot-separated path
+func lookupInput(data Map, path string) (interface{}, bool) {
+	// A top-level key containing dots takes precedence over a path
+	if value, ok := data[path]; ok {
+		return value, true
+	}
+
+	var current interface{} = map[string]interface{}(data)
+	for _, key := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
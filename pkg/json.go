@@ -0,0 +1,89 @@
+package binigo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONCodec encodes and decodes JSON for the framework: responses, request
+// binding, input helpers, validation, SSE events and WebSocket messages.
+// Set one with Application.SetJSONCodec to use a faster implementation.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewEncoder(w io.Writer) JSONEncoder
+}
+
+// JSONEncoder writes JSON values to a stream
+type JSONEncoder interface {
+	Encode(v interface{}) error
+}
+
+// StdJSONCodec is a JSONCodec backed by encoding/json. The default codec is
+// StdJSONCodec{EscapeHTML: true}, which writes what json.Marshal does.
+// Pretty printing or unescaped HTML are opt-ins, for example in debug mode:
+//
+//	app.SetJSONCodec(binigo.StdJSONCodec{Indent: "  ", EscapeHTML: true})
+type StdJSONCodec struct {
+	Indent     string // indent for pretty printing; empty writes compact JSON
+	EscapeHTML bool   // escape <, > and & in strings
+}
+
+// Marshal implements JSONCodec
+func (s StdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	if s.Indent == "" && s.EscapeHTML {
+		return json.Marshal(v)
+	}
+
+	var b bytes.Buffer
+	if err := s.NewEncoder(&b).Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode terminates each value with a newline, Marshal does not
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// Unmarshal implements JSONCodec
+func (s StdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewEncoder implements JSONCodec
+func (s StdJSONCodec) NewEncoder(w io.Writer) JSONEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(s.EscapeHTML)
+	if s.Indent != "" {
+		enc.SetIndent("", s.Indent)
+	}
+	return enc
+}
+
+// defaultJSONCodec matches encoding/json: compact output with HTML escaping
+var defaultJSONCodec JSONCodec = StdJSONCodec{EscapeHTML: true}
+
+// SetJSONCodec replaces the JSON codec. nil restores the default.
+func (a *Application) SetJSONCodec(codec JSONCodec) {
+	if codec == nil {
+		a.jsonCodec.Store(nil)
+		return
+	}
+	a.jsonCodec.Store(&codec)
+}
+
+// JSONCodec returns the codec the framework uses for JSON
+func (a *Application) JSONCodec() JSONCodec {
+	if codec := a.jsonCodec.Load(); codec != nil {
+		return *codec
+	}
+	return defaultJSONCodec
+}
+
+// jsonCodec returns the application's codec, or the default without one
+func (c *Context) jsonCodec() JSONCodec {
+	if c.app != nil {
+		return c.app.JSONCodec()
+	}
+	return defaultJSONCodec
+}
@@ -0,0 +1,53 @@
+package binigo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestContextJSONBody(t *testing.T) {
+	data := Map{"html": "<b>Tom & Jerry</b>", "items": []int{1, 2}}
+	marshalled, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		codec  JSONCodec
+		want   string
+	}{
+		{"default matches json.Marshal", nil, nil, string(marshalled)},
+		{"debug mode does not indent", &Config{Debug: true}, nil, string(marshalled)},
+		{
+			"indent opt-in",
+			nil,
+			StdJSONCodec{Indent: "  ", EscapeHTML: true},
+			"{\n  \"html\": \"\\u003cb\\u003eTom \\u0026 Jerry\\u003c/b\\u003e\",\n  \"items\": [\n    1,\n    2\n  ]\n}",
+		},
+		{"unescaped opt-in", nil, StdJSONCodec{}, `{"html":"<b>Tom & Jerry</b>","items":[1,2]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := NewApplication(tt.config)
+			app.SetJSONCodec(tt.codec)
+			app.Get("/data", func(ctx *Context) error {
+				return ctx.JSON(data)
+			})
+
+			fastCtx := newRequestCtx(fasthttp.MethodGet, "/data")
+			app.Handler()(fastCtx)
+
+			if body := string(fastCtx.Response.Body()); body != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, body)
+			}
+			if marshalled, _ := app.JSONCodec().Marshal(data); string(marshalled) != tt.want {
+				t.Fatalf("expected Marshal to write %q, got %q", tt.want, marshalled)
+			}
+		})
+	}
+}
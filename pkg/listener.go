@@ -0,0 +1,164 @@
+package binigo
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// BindMode controls what Run does when the requested port is taken
+type BindMode int
+
+const (
+	// BindAuto falls back to the next free port in development and binds
+	// strictly in every other environment
+	BindAuto BindMode = iota
+	// BindStrict fails if the requested address cannot be bound
+	BindStrict
+	// BindFallback tries up to 100 following ports before giving up
+	BindFallback
+)
+
+// Address prefixes accepted by Run and RunTLS besides host:port
+const (
+	unixAddrPrefix    = "unix:"
+	systemdAddrPrefix = "systemd:"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd socket activation
+const listenFdsStart = 3
+
+// listen binds addr, which is either host:port, unix:/path/to.sock or
+// systemd:[name] for a socket inherited through systemd socket activation
+func (a *Application) listen(addr string) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(addr, unixAddrPrefix):
+		return listenUnix(strings.TrimPrefix(addr, unixAddrPrefix))
+	case strings.HasPrefix(addr, systemdAddrPrefix):
+		return InheritedListener(strings.TrimPrefix(addr, systemdAddrPrefix))
+	default:
+		return a.listenTCP(addr)
+	}
+}
+
+// bindMode resolves BindAuto against the configured environment
+func (a *Application) bindMode() BindMode {
+	if a.config == nil {
+		return BindStrict
+	}
+
+	mode := a.config.Server.BindMode
+	if mode != BindAuto {
+		return mode
+	}
+	if a.config.Environment == "development" {
+		return BindFallback
+	}
+	return BindStrict
+}
+
+// listenTCP binds addr. In fallback mode a taken port moves the server to
+// the next free one; otherwise the bind error is returned.
+func (a *Application) listenTCP(addr string) (net.Listener, error) {
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+
+	if a.bindMode() != BindFallback {
+		return net.Listen("tcp4", addr)
+	}
+
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", portStr)
+	}
+
+	// Try up to 100 ports, binding directly so no other process can take
+	// the port between probing and listening
+	var firstErr error
+	for i := 0; i < 100; i++ {
+		ln, err := net.Listen("tcp4", net.JoinHostPort(host, strconv.Itoa(port+i)))
+		if err == nil {
+			if i > 0 {
+				log.Printf("Port %d is in use, using port %d instead", port, port+i)
+			}
+			return ln, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// listenUnix binds a Unix domain socket, removing a stale socket file left
+// behind by a previous process
+func listenUnix(path string) (net.Listener, error) {
+	if path == "" {
+		return nil, fmt.Errorf("unix socket path is empty")
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+
+	return net.Listen("unix", path)
+}
+
+// InheritedListener returns a listener passed in through systemd socket
+// activation (LISTEN_PID/LISTEN_FDS). An empty name selects the first
+// socket; otherwise the socket is looked up in LISTEN_FDNAMES.
+func InheritedListener(name string) (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("no sockets passed by systemd (LISTEN_PID not set for this process)")
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, errors.New("no sockets passed by systemd (LISTEN_FDS not set)")
+	}
+
+	offset := 0
+	if name != "" {
+		offset = -1
+		for i, fdName := range strings.Split(os.Getenv("LISTEN_FDNAMES"), ":") {
+			if fdName == name && i < count {
+				offset = i
+				break
+			}
+		}
+		if offset < 0 {
+			return nil, fmt.Errorf("no socket named %q passed by systemd", name)
+		}
+	}
+
+	file := os.NewFile(uintptr(listenFdsStart+offset), "systemd:"+name)
+	if file == nil {
+		return nil, fmt.Errorf("invalid inherited file descriptor %d", listenFdsStart+offset)
+	}
+	defer file.Close()
+
+	// FileListener duplicates the descriptor, so the original can be closed
+	ln, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("inherited socket: %w", err)
+	}
+	return ln, nil
+}
@@ -57,6 +57,12 @@ func LoggerMiddleware() MiddlewareFunc {
 			duration := time.Since(start)
 			status := ctx.fastCtx.Response.StatusCode()
 
+			// Errors are rendered after the chain returns, so log the status
+			// the error handler will send rather than the one set so far
+			if err != nil {
+				status = ToHTTPError(err).Status
+			}
+
 			// Color code based on status
 			statusColor := getStatusColor(status)
 			methodColor := getMethodColor(method)
@@ -140,14 +146,16 @@ func getMethodColor(method string) string {
 // RecoveryMiddleware recovers from panics
 func RecoveryMiddleware() MiddlewareFunc {
 	return func(next HandlerFunc) HandlerFunc {
-		return func(ctx *Context) error {
+		return func(ctx *Context) (err error) {
 			defer func() {
-				if err := recover(); err != nil {
-					log.Printf("PANIC: %v\n%s", err, debug.Stack())
-
-					_ = ctx.Status(500).JSON(Map{
-						"error": "Internal Server Error",
-					})
+				if r := recover(); r != nil {
+					stack := debug.Stack()
+					log.Printf("PANIC: %v\n%s", r, stack)
+
+					// Hand the panic to the application error handler
+					httpErr := NewHTTPError(500).WithCode("internal_error").Wrap(fmt.Errorf("panic: %v", r))
+					httpErr.stack = stack
+					err = httpErr
 				}
 			}()
 
@@ -0,0 +1,57 @@
+package binigo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestLoggerRecordsErrorStatus(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	app := NewApplication(nil)
+	app.Use(LoggerMiddleware())
+	app.Post("/created", func(ctx *Context) error {
+		return ctx.Status(201).String("ok")
+	})
+	app.Get("/teapot", func(ctx *Context) error {
+		return NewHTTPError(418)
+	})
+	app.Get("/invalid", func(ctx *Context) error {
+		return &ValidationError{Errors: map[string][]string{"name": {"required"}}}
+	})
+	app.Get("/boom", func(ctx *Context) error {
+		return fmt.Errorf("database on fire")
+	})
+
+	handler := app.Handler()
+	for _, request := range []struct{ method, path string }{
+		{fasthttp.MethodPost, "/created"},
+		{fasthttp.MethodGet, "/teapot"},
+		{fasthttp.MethodGet, "/invalid"},
+		{fasthttp.MethodGet, "/boom"},
+		{fasthttp.MethodGet, "/missing"},
+	} {
+		handler(newRequestCtx(request.method, request.path))
+	}
+
+	data, err := os.ReadFile(filepath.Join("storage", "logs", "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	want := []string{" 201 ", " 418 ", " 422 ", " 500 ", " 404 "}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d log lines, got %q", len(want), lines)
+	}
+	for i, status := range want {
+		if !strings.Contains(lines[i], status) {
+			t.Errorf("expected status%sin %q", status, lines[i])
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package binigo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// offerPreference orders offers that the client accepts equally, such as
+// when it sends */* or no Accept header at all
+var offerPreference = []string{
+	"application/json",
+	"text/html",
+	"application/xml",
+	"text/plain",
+	"application/yaml",
+	"text/csv",
+	"application/msgpack",
+}
+
+// Negotiate picks the response format from the Accept header, honouring
+// q-values and preferring the most specific match. offers maps media types
+// to the data to send in that format; a value may also be a func() error
+// that writes the response itself, so expensive work only runs for the
+// chosen format. It returns a 406 error when no offer is acceptable.
+//
+//	return ctx.Negotiate(binigo.Map{
+//		"application/json": users,
+//		"text/csv":         users,
+//		"text/html":        func() error { return ctx.HTML(renderUsers(users)) },
+//	})
+func (c *Context) Negotiate(offers Map) error {
+	types := make([]string, 0, len(offers))
+	for mediaType := range offers {
+		types = append(types, mediaType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		ri, rj := offerRank(types[i]), offerRank(types[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+
+	c.fastCtx.Response.Header.Add("Vary", "Accept")
+
+	chosen := negotiateContentType(c.Header("Accept"), types)
+	if chosen == "" {
+		return NewHTTPError(fasthttp.StatusNotAcceptable).
+			WithCode("not_acceptable").
+			WithDetails(Map{"available": types})
+	}
+
+	return c.render(chosen, offers[chosen])
+}
+
+// render writes data in the given media type, keeping the offered content
+// type when a renderer for an alias is used
+func (c *Context) render(mediaType string, data interface{}) error {
+	if fn, ok := data.(func() error); ok {
+		return fn()
+	}
+
+	base := mediaTypeBase(mediaType)
+
+	var err error
+	switch {
+	case base == "application/json" || strings.HasSuffix(base, "+json"):
+		err = c.JSON(data)
+	case base == "application/xml" || base == "text/xml" || strings.HasSuffix(base, "+xml"):
+		err = c.XML(data)
+	case base == "application/yaml" || base == "application/x-yaml" || base == "text/yaml" || base == "text/x-yaml":
+		err = c.YAML(data)
+	case base == "text/csv":
+		err = c.CSV(data)
+	case base == "application/msgpack" || base == "application/x-msgpack" || base == "application/vnd.msgpack":
+		err = c.MsgPack(data)
+	case base == "text/html":
+		err = c.HTML(fmt.Sprint(data))
+	case base == "text/plain":
+		err = c.String("%v", data)
+	default:
+		switch body := data.(type) {
+		case string:
+			c.fastCtx.SetBodyString(body)
+		case []byte:
+			c.fastCtx.SetBody(body)
+		default:
+			return fmt.Errorf("binigo: no renderer for %s", mediaType)
+		}
+		c.fastCtx.Response.Header.SetContentType(mediaType)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if mediaTypeBase(string(c.fastCtx.Response.Header.ContentType())) != base {
+		c.fastCtx.Response.Header.SetContentType(mediaType)
+	}
+	return nil
+}
+
+// acceptRange is one media range from an Accept header
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept parses an Accept header. A missing header accepts anything.
+func parseAccept(header string) []acceptRange {
+	if strings.TrimSpace(header) == "" {
+		return []acceptRange{{typ: "*", subtype: "*", q: 1}}
+	}
+
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+		if mediaRange == "*" {
+			mediaRange = "*/*"
+		}
+
+		typ, subtype, ok := strings.Cut(mediaRange, "/")
+		if !ok || typ == "" || subtype == "" {
+			continue
+		}
+
+		r := acceptRange{typ: typ, subtype: subtype, q: 1}
+		valid := true
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.ToLower(strings.TrimSpace(key)) != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || q < 0 || q > 1 {
+				valid = false
+				break
+			}
+			r.q = q
+		}
+
+		if valid {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// negotiateContentType returns the offer the client prefers, or "" when
+// none is acceptable. Each offer takes the q-value of the most specific
+// range matching it; ties go to the more specific match and then to the
+// earlier offer.
+func negotiateContentType(accept string, offers []string) string {
+	ranges := parseAccept(accept)
+
+	best, bestQ, bestSpecificity := "", 0.0, -1
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(mediaTypeBase(offer), "/")
+
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := -1
+			switch {
+			case r.typ == typ && r.subtype == subtype:
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			}
+			if s > specificity || (s == specificity && s >= 0 && r.q > q) {
+				q, specificity = r.q, s
+			}
+		}
+
+		if specificity < 0 || q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && specificity > bestSpecificity) {
+			best, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+
+	return best
+}
+
+// offerRank orders known media types by offerPreference, others after them
+func offerRank(mediaType string) int {
+	base := mediaTypeBase(mediaType)
+	for i, preferred := range offerPreference {
+		if base == preferred {
+			return i
+		}
+	}
+	return len(offerPreference)
+}
+
+// mediaTypeBase strips parameters from a media type and lowercases it
+func mediaTypeBase(mediaType string) string {
+	base, _, _ := strings.Cut(mediaType, ";")
+	return strings.ToLower(strings.TrimSpace(base))
+}
@@ -0,0 +1,281 @@
+package binigo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XML sends data as XML. Maps, including Map, become a <response> element
+// with one child per key in sorted order.
+func (c *Context) XML(data interface{}) error {
+	body, err := marshalXML(data)
+	if err != nil {
+		return err
+	}
+
+	c.fastCtx.Response.Header.SetContentType("application/xml; charset=utf-8")
+	c.fastCtx.SetBodyString(xml.Header)
+	c.fastCtx.Response.AppendBody(body)
+	return nil
+}
+
+// YAML sends data as a YAML document. Field names follow json struct tags.
+func (c *Context) YAML(data interface{}) error {
+	body, err := marshalYAML(c.jsonCodec(), data)
+	if err != nil {
+		return err
+	}
+
+	c.fastCtx.Response.Header.SetContentType("application/yaml; charset=utf-8")
+	c.fastCtx.SetBody(body)
+	return nil
+}
+
+// MsgPack sends data encoded as MessagePack. Field names follow json
+// struct tags.
+func (c *Context) MsgPack(data interface{}) error {
+	body, err := marshalMsgPack(c.jsonCodec(), data)
+	if err != nil {
+		return err
+	}
+
+	c.fastCtx.Response.Header.SetContentType("application/msgpack")
+	c.fastCtx.SetBody(body)
+	return nil
+}
+
+// CSV sends a slice of structs as CSV with a header row. Columns are the
+// exported fields named by their `csv` tag, then their `json` tag, then
+// the field name; a tag of "-" skips the field. A [][]string is sent as
+// is. With a filename the response is sent as an attachment.
+func (c *Context) CSV(data interface{}, filename ...string) error {
+	records, err := csvRecords(data)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+
+	c.fastCtx.Response.Header.SetContentType("text/csv; charset=utf-8")
+	if len(filename) > 0 && filename[0] != "" {
+		c.SetHeader("Content-Disposition", contentDisposition("attachment", filename[0]))
+	}
+	c.fastCtx.SetBody(b.Bytes())
+	return nil
+}
+
+// marshalXML encodes data, converting maps that encoding/xml cannot handle
+func marshalXML(data interface{}) ([]byte, error) {
+	switch v := data.(type) {
+	case Map:
+		return xml.Marshal(xmlMap{name: "response", values: v})
+	case map[string]interface{}:
+		return xml.Marshal(xmlMap{name: "response", values: v})
+	}
+	return xml.Marshal(data)
+}
+
+// xmlMap encodes a map as an element with one child element per key
+type xmlMap struct {
+	name   string
+	values map[string]interface{}
+}
+
+// MarshalXML implements xml.Marshaler
+func (m xmlMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: m.name}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := encodeXMLValue(e, key, m.values[key]); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// encodeXMLValue writes value as element name. Slices repeat the element
+// once per item and nil values produce an empty element.
+func encodeXMLValue(e *xml.Encoder, name string, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+		return e.EncodeToken(start.End())
+	case Map:
+		return e.Encode(xmlMap{name: name, values: v})
+	case map[string]interface{}:
+		return e.Encode(xmlMap{name: name, values: v})
+	case []Map:
+		for _, item := range v {
+			if err := encodeXMLValue(e, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXMLValue(e, name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// csvRecords converts a slice of structs, or pointers to structs, into a
+// header row followed by one row per element
+func csvRecords(data interface{}) ([][]string, error) {
+	if records, ok := data.([][]string); ok {
+		return records, nil
+	}
+
+	value := reflect.ValueOf(data)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("csv: expected a slice of structs, got %T", data)
+	}
+
+	elemType := value.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv: expected a slice of structs, got %T", data)
+	}
+
+	columns := csvColumns(elemType, nil)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+
+	records := make([][]string, 0, value.Len()+1)
+	records = append(records, header)
+	for i := 0; i < value.Len(); i++ {
+		row := value.Index(i)
+		for row.Kind() == reflect.Ptr && !row.IsNil() {
+			row = row.Elem()
+		}
+
+		record := make([]string, len(columns))
+		if row.Kind() == reflect.Struct {
+			for j, column := range columns {
+				field, err := row.FieldByIndexErr(column.index)
+				if err == nil {
+					record[j] = csvValue(field)
+				}
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// csvColumns lists the exported fields of t, flattening untagged embedded structs
+func csvColumns(t reflect.Type, parent []int) []csvColumn {
+	var columns []csvColumn
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if name == "" {
+			name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+		}
+		if name == "-" {
+			continue
+		}
+
+		if name == "" && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				columns = append(columns, csvColumns(embedded, index)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+
+	return columns
+}
+
+// csvValue formats a field for a CSV cell; nil pointers are empty
+func csvValue(field reflect.Value) string {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+
+	if field.CanInterface() {
+		switch value := field.Interface().(type) {
+		case time.Time:
+			return value.Format(time.RFC3339)
+		case fmt.Stringer:
+			return value.String()
+		case encoding.TextMarshaler:
+			if text, err := value.MarshalText(); err == nil {
+				return string(text)
+			}
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String()
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'f', -1, field.Type().Bits())
+	}
+	return fmt.Sprint(field)
+}
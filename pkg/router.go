@@ -1,19 +1,49 @@
 package binigo
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/valyala/fasthttp"
 )
 
-// Router manages application routes
+// Router manages application routes. Groups are routers that share the
+// route table of their parent.
 type Router struct {
-	routes     map[string][]*Route
+	table      *routeTable
 	middleware []MiddlewareFunc
 	prefix     string
+	namePrefix string
+	domain     string
 	parent     *Router
 }
 
+// routeTable holds the routes shared by a router and all of its groups
+// along with the lookup structures compiled from them
+type routeTable struct {
+	routes   []*Route
+	statics  []*Route // Static mounts, tried when no route matches
+	compiled atomic.Pointer[compiledRoutes]
+	mu       sync.Mutex
+}
+
+// compiledRoutes is an immutable snapshot of the route table
+type compiledRoutes struct {
+	trees   map[string]*node            // method -> tree for routes without a domain
+	domains map[string]map[string]*node // domain -> method -> tree
+	named   map[string]*Route
+	statics []*routeLeaf // longest prefix first
+}
+
 // Route represents a single route definition
 type Route struct {
 	method     string
@@ -21,14 +51,16 @@ type Route struct {
 	handler    HandlerFunc
 	middleware []MiddlewareFunc
 	name       string
-	pattern    *regexp.Regexp
+	segments   []routeSegment
 	paramNames []string
+	group      *Router
+	timeout    time.Duration
 }
 
 // NewRouter creates a new router instance
 func NewRouter() *Router {
 	return &Router{
-		routes:     make(map[string][]*Route),
+		table:      &routeTable{},
 		middleware: make([]MiddlewareFunc, 0),
 	}
 }
@@ -40,67 +72,387 @@ func (r *Router) Add(method, path string, handler HandlerFunc) *Route {
 		path:       r.prefix + path,
 		handler:    handler,
 		middleware: make([]MiddlewareFunc, 0),
+		group:      r,
 	}
 
-	// Compile route pattern
+	// Parse route path into segments
 	route.compile()
 
 	// Store route
-	if r.routes[route.method] == nil {
-		r.routes[route.method] = make([]*Route, 0)
-	}
-	r.routes[route.method] = append(r.routes[route.method], route)
+	r.table.add(route)
 
 	return route
 }
 
-// compile creates regex pattern from route path
+// compile parses the Laravel-style route path into matchable segments
 func (route *Route) compile() {
-	// Convert Laravel-style {param} to regex
-	pattern := route.path
-	pattern = regexp.MustCompile(`\{(\w+)\}`).ReplaceAllStringFunc(pattern, func(match string) string {
-		// Extract parameter name
-		paramName := strings.Trim(match, "{}")
-		route.paramNames = append(route.paramNames, paramName)
-		return `([^/]+)`
+	segments, err := parseRoutePath(route.path)
+	if err != nil {
+		panic(fmt.Sprintf("binigo: invalid route %s %s: %v", route.method, route.path, err))
+	}
+
+	route.segments = segments
+	route.paramNames = route.paramNames[:0]
+	for _, segment := range segments {
+		if segment.kind != staticSegment {
+			route.paramNames = append(route.paramNames, segment.name)
+		}
+	}
+}
+
+// fullName returns the route name prefixed by its groups' name prefixes
+func (route *Route) fullName() string {
+	if route.name == "" {
+		return ""
+	}
+
+	name := route.name
+	for g := route.group; g != nil; g = g.parent {
+		name = g.namePrefix + name
+	}
+	return name
+}
+
+// domain returns the host the route is restricted to, if any
+func (route *Route) domain() string {
+	for g := route.group; g != nil; g = g.parent {
+		if g.domain != "" {
+			return g.domain
+		}
+	}
+	return ""
+}
+
+// buildHandler wraps the route handler with route middleware and the
+// middleware of every enclosing group, outermost group first
+func (route *Route) buildHandler() HandlerFunc {
+	handler := route.handler
+
+	// Apply route-specific middleware
+	for i := len(route.middleware) - 1; i >= 0; i-- {
+		handler = route.middleware[i](handler)
+	}
+
+	// Apply group middleware from the innermost group outwards
+	for g := route.group; g != nil; g = g.parent {
+		for i := len(g.middleware) - 1; i >= 0; i-- {
+			handler = g.middleware[i](handler)
+		}
+	}
+
+	return handler
+}
+
+// add stores a route and discards the compiled lookup structures
+func (t *routeTable) add(route *Route) {
+	t.update(func() {
+		t.routes = append(t.routes, route)
 	})
+}
 
-	// Convert Laravel-style {param?} optional parameters
-	pattern = regexp.MustCompile(`\{(\w+)\?\}`).ReplaceAllStringFunc(pattern, func(match string) string {
-		paramName := strings.Trim(match, "{?}")
-		route.paramNames = append(route.paramNames, paramName)
-		return `(?:([^/]+))?`
+// update applies a change to routes or groups under the table lock and
+// discards the compiled lookup structures so they are rebuilt on next use.
+// Requests being served keep the snapshot they started with.
+func (t *routeTable) update(change func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	change()
+	t.compiled.Store(nil)
+}
+
+// snapshot returns the compiled routes, building them if needed
+func (t *routeTable) snapshot() *compiledRoutes {
+	if compiled := t.compiled.Load(); compiled != nil {
+		return compiled
+	}
+	return t.build()
+}
+
+// build compiles one radix tree per domain and HTTP method, the named
+// route index and each route's middleware chain
+func (t *routeTable) build() *compiledRoutes {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if compiled := t.compiled.Load(); compiled != nil {
+		return compiled
+	}
+
+	compiled := &compiledRoutes{
+		trees:   make(map[string]*node),
+		domains: make(map[string]map[string]*node),
+		named:   make(map[string]*Route),
+	}
+
+	for _, route := range t.routes {
+		trees := compiled.trees
+		if domain := strings.ToLower(route.domain()); domain != "" {
+			if compiled.domains[domain] == nil {
+				compiled.domains[domain] = make(map[string]*node)
+			}
+			trees = compiled.domains[domain]
+		}
+
+		root, ok := trees[route.method]
+		if !ok {
+			root = &node{kind: staticSegment}
+			trees[route.method] = root
+		}
+
+		handler := route.buildHandler()
+		for _, variant := range expandOptional(route.segments) {
+			root.insert(variant, route, handler)
+		}
+
+		if name := route.fullName(); name != "" {
+			compiled.named[name] = route
+		}
+	}
+
+	for _, route := range t.statics {
+		compiled.statics = append(compiled.statics, &routeLeaf{
+			route:      route,
+			handler:    route.buildHandler(),
+			paramNames: route.paramNames,
+		})
+
+		if name := route.fullName(); name != "" {
+			if _, exists := compiled.named[name]; !exists {
+				compiled.named[name] = route
+			}
+		}
+	}
+	sort.SliceStable(compiled.statics, func(i, j int) bool {
+		return len(compiled.statics[i].route.path) > len(compiled.statics[j].route.path)
 	})
 
-	// Compile the pattern
-	pattern = "^" + pattern + "$"
-	route.pattern = regexp.MustCompile(pattern)
+	t.compiled.Store(compiled)
+	return compiled
+}
+
+// treesFor returns the trees to search for a host: the host's own trees
+// first, then the trees of routes without a domain
+func (c *compiledRoutes) treesFor(host string) []map[string]*node {
+	if len(c.domains) > 0 && host != "" {
+		if trees, ok := c.domains[strings.ToLower(host)]; ok {
+			return []map[string]*node{trees, c.trees}
+		}
+	}
+	return []map[string]*node{c.trees}
+}
+
+// match finds the route leaf for host, method and path
+func (t *routeTable) match(host, method, path string, values *[]string) *routeLeaf {
+	compiled := t.snapshot()
+
+	if len(compiled.domains) > 0 && host != "" {
+		if trees, ok := compiled.domains[strings.ToLower(host)]; ok {
+			if root := trees[method]; root != nil {
+				if leaf := root.match(path, values); leaf != nil {
+					return leaf
+				}
+			}
+		}
+	}
+
+	root := compiled.trees[method]
+	if root == nil {
+		return nil
+	}
+	return root.match(path, values)
+}
+
+// allowedMethods lists the methods that have a route matching path,
+// including the implicit HEAD and OPTIONS handling
+func (t *routeTable) allowedMethods(host, path string) []string {
+	var (
+		allowed []string
+		values  []string
+	)
+
+	for _, trees := range t.snapshot().treesFor(host) {
+		for method, root := range trees {
+			if containsString(allowed, method) {
+				continue
+			}
+
+			values = values[:0]
+			if root.match(path, &values) != nil {
+				allowed = append(allowed, method)
+			}
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if containsString(allowed, fasthttp.MethodGet) && !containsString(allowed, fasthttp.MethodHead) {
+		allowed = append(allowed, fasthttp.MethodHead)
+	}
+	if !containsString(allowed, fasthttp.MethodOptions) {
+		allowed = append(allowed, fasthttp.MethodOptions)
+	}
+
+	sort.Strings(allowed)
+	return allowed
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
 
 // Middleware adds middleware to this specific route
 func (route *Route) Middleware(middleware ...MiddlewareFunc) *Route {
-	route.middleware = append(route.middleware, middleware...)
+	route.group.table.update(func() {
+		route.middleware = append(route.middleware, middleware...)
+	})
 	return route
 }
 
-// Name sets the route name
+// Where constrains a route parameter to a named constraint (int, alpha,
+// alpha_num, uuid) or a regular expression that must match the whole value.
+// Requests that do not satisfy it fall through to the next matching route.
+func (route *Route) Where(param, pattern string) *Route {
+	constraint, err := newParamConstraint(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("binigo: invalid route %s %s: %v", route.method, route.path, err))
+	}
+
+	route.group.table.update(func() {
+		for i := range route.segments {
+			if route.segments[i].kind != staticSegment && route.segments[i].name == param {
+				route.segments[i].constraint = constraint
+			}
+		}
+	})
+	return route
+}
+
+// Name sets the route name used for URL generation. Names of routes in
+// a group are prefixed with the group's name prefix.
 func (route *Route) Name(name string) *Route {
-	route.name = name
+	route.group.table.update(func() {
+		route.name = name
+	})
 	return route
 }
 
+// Timeout sets a deadline for the request context returned by
+// Context.Context. Handlers and queries using that context observe
+// cancellation once the timeout expires; the handler itself is not aborted.
+func (route *Route) Timeout(timeout time.Duration) *Route {
+	route.group.table.update(func() {
+		route.timeout = timeout
+	})
+	return route
+}
+
+// URL builds the path for a named route. Values for route parameters are
+// substituted into the template; any remaining values become the query string.
+// Optional parameters without a value are dropped from the path.
+func (r *Router) URL(name string, params ...Map) (string, error) {
+	route, ok := r.table.snapshot().named[name]
+	if !ok {
+		return "", fmt.Errorf("route not found: %s", name)
+	}
+
+	values := make(map[string]string)
+	for _, set := range params {
+		for key, value := range set {
+			if value != nil {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	// Where may change the route's segments concurrently
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+	return route.url(values)
+}
+
+// url fills the route template, picking the longest variant whose
+// parameters all have values
+func (route *Route) url(values map[string]string) (string, error) {
+	var missing string
+
+variants:
+	for _, variant := range expandOptional(route.segments) {
+		var path strings.Builder
+		used := make(map[string]bool)
+
+		for _, segment := range variant {
+			if segment.kind == staticSegment {
+				path.WriteString(segment.text)
+				continue
+			}
+
+			value, ok := values[segment.name]
+			if !ok || value == "" {
+				if missing == "" {
+					missing = segment.name
+				}
+				continue variants
+			}
+
+			if segment.constraint != nil && !segment.constraint.match(value) {
+				return "", fmt.Errorf("parameter %s=%q does not satisfy constraint %q for route %s",
+					segment.name, value, segment.constraint.pattern, route.fullName())
+			}
+
+			if segment.kind == catchAllSegment {
+				parts := strings.Split(value, "/")
+				for i, part := range parts {
+					parts[i] = url.PathEscape(part)
+				}
+				path.WriteString(strings.Join(parts, "/"))
+			} else {
+				path.WriteString(url.PathEscape(value))
+			}
+			used[segment.name] = true
+		}
+
+		// Everything not consumed by the path goes to the query string
+		query := url.Values{}
+		for key, value := range values {
+			if !used[key] {
+				query.Set(key, value)
+			}
+		}
+
+		if len(query) > 0 {
+			return path.String() + "?" + query.Encode(), nil
+		}
+		return path.String(), nil
+	}
+
+	return "", fmt.Errorf("missing parameter %s for route %s", missing, route.fullName())
+}
+
 // Match checks if the route matches the given path
 func (route *Route) Match(path string) (bool, map[string]string) {
-	matches := route.pattern.FindStringSubmatch(path)
-	if matches == nil {
+	root := &node{kind: staticSegment}
+	for _, variant := range expandOptional(route.segments) {
+		root.insert(variant, route, route.handler)
+	}
+
+	var values []string
+	leaf := root.match(path, &values)
+	if leaf == nil {
 		return false, nil
 	}
 
-	params := make(map[string]string)
-	for i, name := range route.paramNames {
-		if i+1 < len(matches) && matches[i+1] != "" {
-			params[name] = matches[i+1]
-		}
+	params := make(map[string]string, len(values))
+	for i, name := range leaf.paramNames {
+		params[name] = values[i]
 	}
 
 	return true, params
@@ -108,64 +460,222 @@ func (route *Route) Match(path string) (bool, map[string]string) {
 
 // Handle processes the incoming request
 func (r *Router) Handle(ctx *Context) error {
-	methodBytes := ctx.fastCtx.Method()
-	pathBytes := ctx.fastCtx.Path()
+	// Method and path are only borrowed for the lookup; captured values are copied
+	method := bytesToString(ctx.fastCtx.Method())
+	path := bytesToString(ctx.fastCtx.Path())
 
-	// Find matching route
-	routes, ok := r.routes[string(methodBytes)]
-	if !ok {
-		return ctx.Status(404).JSON(Map{
-			"error": "Not Found",
-		})
-	}
+	host := hostWithoutPort(bytesToString(ctx.fastCtx.Host()))
 
-	for _, route := range routes {
-		if matched, params := route.Match(string(pathBytes)); matched {
-			// Set route parameters
-			ctx.params = params
-			ctx.route = route
+	// Reuse the context's buffer so parameter routes don't allocate a slice
+	values := ctx.paramValues[:0]
+	leaf := r.table.match(host, method, path, &values)
 
-			// Build handler with route middleware
-			handler := route.handler
+	// Serve HEAD through the GET handler; fasthttp still sends Content-Length
+	if leaf == nil && method == fasthttp.MethodHead {
+		if leaf = r.table.match(host, fasthttp.MethodGet, path, &values); leaf != nil {
+			ctx.fastCtx.Response.SkipBody = true
+		}
+	}
+	ctx.paramValues = values[:0]
 
-			// Apply route-specific middleware
-			for i := len(route.middleware) - 1; i >= 0; i-- {
-				handler = route.middleware[i](handler)
+	if leaf == nil {
+		if method == fasthttp.MethodGet || method == fasthttp.MethodHead {
+			if served, err := r.table.serveStatic(ctx, host, path); served {
+				return err
 			}
+		}
 
-			// Apply router group middleware
-			for i := len(r.middleware) - 1; i >= 0; i-- {
-				handler = r.middleware[i](handler)
-			}
+		allowed := r.table.allowedMethods(host, path)
+		if len(allowed) == 0 {
+			return NewHTTPError(404).WithCode("not_found")
+		}
 
-			return handler(ctx)
+		ctx.SetHeader("Allow", strings.Join(allowed, ", "))
+
+		// Answer OPTIONS from the route table when no explicit route exists
+		if method == fasthttp.MethodOptions {
+			ctx.Status(204)
+			return nil
 		}
+
+		return NewHTTPError(405).WithCode("method_not_allowed")
 	}
 
-	return ctx.Status(404).JSON(Map{
-		"error": "Not Found",
-	})
+	// Set route parameters
+	for i, name := range leaf.paramNames {
+		ctx.setParam(name, strings.Clone(values[i]))
+	}
+	ctx.route = leaf.route
+
+	// Apply the deadline now the route is known. Middleware may already
+	// have derived the request context, so wrap it rather than replace it.
+	if leaf.timeout > 0 {
+		ctx.setDeadline(ctx.fastCtx.Time().Add(leaf.timeout))
+	}
+
+	// The chain already includes route and group middleware
+	return leaf.handler(ctx)
 }
 
-// Group creates a route group
+// serveStatic runs the Static mounts whose prefix matches path, most
+// specific first. It reports false when none of them has the file, so the
+// request gets the usual 404 or 405 response.
+func (t *routeTable) serveStatic(ctx *Context, host, path string) (bool, error) {
+	for _, leaf := range t.snapshot().statics {
+		if domain := leaf.route.domain(); domain != "" && !strings.EqualFold(domain, host) {
+			continue
+		}
+
+		prefix := strings.TrimSuffix(leaf.route.path, "{"+staticParam+"...}")
+		name, ok := strings.CutPrefix(path, prefix)
+		if !ok {
+			continue
+		}
+
+		ctx.setParam(staticParam, strings.Clone(name))
+		ctx.route = leaf.route
+
+		// A missing file lets the next mount or the 404/405 handling answer
+		err := leaf.handler(ctx)
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status == fasthttp.StatusNotFound {
+			delete(ctx.params, staticParam)
+			ctx.route = nil
+			continue
+		}
+		return true, err
+	}
+	return false, nil
+}
+
+// hostWithoutPort strips the port from a Host header value
+func hostWithoutPort(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// Group creates a route group. Routes registered on the group get its
+// path prefix and the middleware, name prefix and domain of the group and
+// all of its ancestors. fn may be nil to configure the group fluently.
+// Groups may be changed while serving; requests already routed keep the
+// configuration they were matched with.
 func (r *Router) Group(prefix string, fn func(router *Router)) *Router {
 	group := &Router{
-		routes:     r.routes,
+		table:      r.table,
 		middleware: make([]MiddlewareFunc, 0),
 		prefix:     r.prefix + prefix,
 		parent:     r,
 	}
 
-	fn(group)
+	if fn != nil {
+		fn(group)
+	}
 	return group
 }
 
-// Middleware adds middleware to the router group
+// Middleware adds middleware to the router group. It also applies to
+// routes that were registered on the group before the call.
 func (r *Router) Middleware(middleware ...MiddlewareFunc) *Router {
-	r.middleware = append(r.middleware, middleware...)
+	r.table.update(func() {
+		r.middleware = append(r.middleware, middleware...)
+	})
 	return r
 }
 
+// Name sets a prefix for the names of routes in the group, e.g. "admin."
+func (r *Router) Name(prefix string) *Router {
+	r.table.update(func() {
+		r.namePrefix = prefix
+	})
+	return r
+}
+
+// Domain restricts the group's routes to requests for the given host
+func (r *Router) Domain(domain string) *Router {
+	r.table.update(func() {
+		r.domain = domain
+	})
+	return r
+}
+
+// RouteInfo describes a registered route for introspection tools such as
+// the route:list command
+type RouteInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Name       string   `json:"name,omitempty"`
+	Domain     string   `json:"domain,omitempty"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`
+}
+
+// Routes returns every registered route in registration order
+func (r *Router) Routes() []RouteInfo {
+	// Groups and routes may still be configured concurrently
+	r.table.mu.Lock()
+	defer r.table.mu.Unlock()
+
+	infos := make([]RouteInfo, 0, len(r.table.routes))
+	for _, route := range r.table.routes {
+		info := RouteInfo{
+			Method:     route.method,
+			Path:       route.path,
+			Name:       route.fullName(),
+			Domain:     route.domain(),
+			Handler:    funcName(route.handler),
+			Middleware: make([]string, 0),
+		}
+
+		// Outermost group first, matching execution order
+		var groups []*Router
+		for g := route.group; g != nil; g = g.parent {
+			groups = append(groups, g)
+		}
+		for i := len(groups) - 1; i >= 0; i-- {
+			for _, mw := range groups[i].middleware {
+				info.Middleware = append(info.Middleware, middlewareName(mw))
+			}
+		}
+		for _, mw := range route.middleware {
+			info.Middleware = append(info.Middleware, middlewareName(mw))
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// funcName returns a short package-qualified name for a function value
+func funcName(fn interface{}) string {
+	value := reflect.ValueOf(fn)
+	if !value.IsValid() || value.IsNil() {
+		return ""
+	}
+
+	f := runtime.FuncForPC(value.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	// Method values carry a -fm suffix: controllers.(*UserController).Index-fm
+	name := strings.TrimSuffix(f.Name(), "-fm")
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// middlewareName names a middleware after the factory that created it,
+// e.g. pkg.AuthMiddleware rather than pkg.AuthMiddleware.func1
+func middlewareName(mw MiddlewareFunc) string {
+	return closureSuffix.ReplaceAllString(funcName(mw), "")
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
 // Route registration methods
 func (r *Router) Get(path string, handler HandlerFunc) *Route {
 	return r.Add("GET", path, handler)
@@ -191,42 +701,151 @@ func (r *Router) Options(path string, handler HandlerFunc) *Route {
 	return r.Add("OPTIONS", path, handler)
 }
 
-// Resource creates RESTful routes for a resource
-func (r *Router) Resource(name string, controller interface{}) {
-	basePath := "/" + name
-
-	// Index - GET /resource
-	r.Get(basePath, func(ctx *Context) error {
-		return fmt.Errorf("index not implemented")
-	}).Name(name + ".index")
-
-	// Create - GET /resource/create
-	r.Get(basePath+"/create", func(ctx *Context) error {
-		return fmt.Errorf("create not implemented")
-	}).Name(name + ".create")
-
-	// Store - POST /resource
-	r.Post(basePath, func(ctx *Context) error {
-		return fmt.Errorf("store not implemented")
-	}).Name(name + ".store")
-
-	// Show - GET /resource/{id}
-	r.Get(basePath+"/{id}", func(ctx *Context) error {
-		return fmt.Errorf("show not implemented")
-	}).Name(name + ".show")
-
-	// Edit - GET /resource/{id}/edit
-	r.Get(basePath+"/{id}/edit", func(ctx *Context) error {
-		return fmt.Errorf("edit not implemented")
-	}).Name(name + ".edit")
-
-	// Update - PUT/PATCH /resource/{id}
-	r.Put(basePath+"/{id}", func(ctx *Context) error {
-		return fmt.Errorf("update not implemented")
-	}).Name(name + ".update")
-
-	// Destroy - DELETE /resource/{id}
-	r.Delete(basePath+"/{id}", func(ctx *Context) error {
-		return fmt.Errorf("destroy not implemented")
-	}).Name(name + ".destroy")
+// ResourceOption customises the routes registered by Resource
+type ResourceOption func(*resourceOptions)
+
+type resourceOptions struct {
+	only   []string
+	except []string
+}
+
+// Only limits a resource to the given actions
+func Only(actions ...string) ResourceOption {
+	return func(o *resourceOptions) {
+		o.only = append(o.only, actions...)
+	}
+}
+
+// Except registers every resource action but the given ones
+func Except(actions ...string) ResourceOption {
+	return func(o *resourceOptions) {
+		o.except = append(o.except, actions...)
+	}
+}
+
+// includes reports whether an action should be registered
+func (o *resourceOptions) includes(action string) bool {
+	if len(o.only) > 0 && !containsString(o.only, action) {
+		return false
+	}
+	return !containsString(o.except, action)
+}
+
+// resourceAction maps a controller method to its RESTful routes
+type resourceAction struct {
+	name    string
+	method  string
+	verbs   []string
+	suffix  string
+	hasForm bool
+}
+
+// resourceActions lists resource actions in registration order
+var resourceActions = []resourceAction{
+	{name: "index", method: "Index", verbs: []string{"GET"}},
+	{name: "create", method: "Create", verbs: []string{"GET"}, suffix: "/create", hasForm: true},
+	{name: "store", method: "Store", verbs: []string{"POST"}},
+	{name: "show", method: "Show", verbs: []string{"GET"}, suffix: "/{id}"},
+	{name: "edit", method: "Edit", verbs: []string{"GET"}, suffix: "/{id}/edit", hasForm: true},
+	{name: "update", method: "Update", verbs: []string{"PUT", "PATCH"}, suffix: "/{id}"},
+	{name: "destroy", method: "Destroy", verbs: []string{"DELETE"}, suffix: "/{id}"},
+}
+
+// Resource creates RESTful routes for a resource, registering only the
+// actions (Index, Create, Store, Show, Edit, Update, Destroy) the controller
+// implements. Dotted names nest resources: "posts.comments" registers
+// /posts/{post}/comments/{id}.
+func (r *Router) Resource(name string, controller interface{}, options ...ResourceOption) []*Route {
+	return r.resource(name, controller, false, options)
+}
+
+// ApiResource is like Resource but skips the HTML form actions create and edit
+func (r *Router) ApiResource(name string, controller interface{}, options ...ResourceOption) []*Route {
+	return r.resource(name, controller, true, options)
+}
+
+func (r *Router) resource(name string, controller interface{}, api bool, options []ResourceOption) []*Route {
+	if controller == nil {
+		panic(fmt.Sprintf("binigo: resource %s has a nil controller", name))
+	}
+
+	opts := &resourceOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+
+	basePath := resourcePath(name)
+	value := reflect.ValueOf(controller)
+	routes := make([]*Route, 0, len(resourceActions)+1)
+
+	// Methods with pointer receivers are missing from a controller passed
+	// by value, which would otherwise register nothing without complaint
+	implemented := false
+	for _, action := range resourceActions {
+		if value.MethodByName(action.method).IsValid() {
+			implemented = true
+			break
+		}
+	}
+	if !implemented {
+		panic(fmt.Sprintf("binigo: resource %s: controller %T has no resource actions (pass a pointer?)", name, controller))
+	}
+
+	for _, action := range resourceActions {
+		if (api && action.hasForm) || !opts.includes(action.name) {
+			continue
+		}
+
+		method := value.MethodByName(action.method)
+		if !method.IsValid() {
+			continue
+		}
+
+		handler, ok := method.Interface().(func(*Context) error)
+		if !ok {
+			panic(fmt.Sprintf("binigo: %T.%s must have signature func(*binigo.Context) error", controller, action.method))
+		}
+
+		for i, verb := range action.verbs {
+			route := r.Add(verb, basePath+action.suffix, handler)
+			if i == 0 {
+				route.Name(name + "." + action.name)
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// resourcePath turns "posts.comments" into "/posts/{post}/comments"
+func resourcePath(name string) string {
+	parts := strings.Split(name, ".")
+
+	var path strings.Builder
+	for i, part := range parts {
+		path.WriteString("/" + part)
+		if i < len(parts)-1 {
+			path.WriteString("/{" + singular(part) + "}")
+		}
+	}
+
+	return path.String()
+}
+
+// singular naively singularises an English resource name
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ss"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
 }
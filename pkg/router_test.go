@@ -0,0 +1,79 @@
+package binigo
+
+import (
+	"strings"
+	"testing"
+)
+
+type valueController struct{}
+
+func (valueController) Index(ctx *Context) error { return nil }
+func (valueController) Show(ctx *Context) error  { return nil }
+
+type pointerController struct{}
+
+func (*pointerController) Index(ctx *Context) error { return nil }
+func (*pointerController) Store(ctx *Context) error { return nil }
+
+func TestResourceRegistersImplementedActions(t *testing.T) {
+	r := NewRouter()
+	r.Resource("photos", valueController{})
+	r.ApiResource("posts.comments", &pointerController{}, Except("store"))
+
+	var got []string
+	for _, route := range r.Routes() {
+		got = append(got, route.Method+" "+route.Path+" "+route.Name)
+	}
+
+	want := []string{
+		"GET /photos photos.index",
+		"GET /photos/{id} photos.show",
+		"GET /posts/{post}/comments posts.comments.index",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected routes\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestResourcePanicsWithoutActions(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "has no resource actions (pass a pointer?)") {
+			t.Fatalf("expected a panic about missing actions, got %q", msg)
+		}
+	}()
+
+	NewRouter().Resource("ptrs", pointerController{})
+}
+
+func TestGroupChangesWhileServing(t *testing.T) {
+	r := NewRouter()
+	api := r.Group("/api", nil)
+	api.Get("/users/{id}", func(ctx *Context) error {
+		return ctx.String("%s", ctx.Param("id"))
+	}).Name("show")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			api.Middleware(func(next HandlerFunc) HandlerFunc { return next })
+			api.Name("api.")
+			api.Domain("")
+			r.Routes()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		ctx := &Context{fastCtx: newRequestCtx("GET", "/api/users/1")}
+		if err := r.Handle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		r.URL("api.show", Map{"id": 1})
+	}
+	<-done
+
+	if url, err := r.URL("api.show", Map{"id": 1}); err != nil || url != "/api/users/1" {
+		t.Fatalf("expected /api/users/1, got %q (%v)", url, err)
+	}
+}
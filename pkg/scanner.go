@@ -0,0 +1,294 @@
+package binigo
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// fieldMapCache holds column name -> field index paths per struct type
+var fieldMapCache sync.Map
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// scanRows scans every row into dest, which must be a pointer to a slice of
+// structs, struct pointers, Maps or scalars
+func scanRows(rows *sql.Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return fmt.Errorf("dest must be a pointer")
+	}
+
+	sliceValue := destValue.Elem()
+	if sliceValue.Kind() != reflect.Slice {
+		return fmt.Errorf("dest must be a pointer to a slice")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	baseType := elemType
+	if isPtr {
+		baseType = elemType.Elem()
+	}
+
+	// Start from an empty, non-nil slice so no rows encodes as [] rather than null
+	sliceValue.Set(reflect.MakeSlice(sliceValue.Type(), 0, 0))
+
+	for rows.Next() {
+		elem := reflect.New(baseType)
+
+		if err := scanRow(rows, columns, elem); err != nil {
+			return err
+		}
+
+		if isPtr {
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanOne scans the first row into dest, returning sql.ErrNoRows if there is none
+func scanOne(rows *sql.Rows, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return fmt.Errorf("dest must be a pointer")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+
+	// Allow **T for structs so callers can pass the address of a nil pointer
+	target := destValue
+	if inner := destValue.Elem(); inner.Kind() == reflect.Ptr && inner.Type().Elem().Kind() == reflect.Struct &&
+		!isScalarType(inner.Type().Elem()) {
+		if inner.IsNil() {
+			inner.Set(reflect.New(inner.Type().Elem()))
+		}
+		target = inner
+	}
+
+	if err := scanRow(rows, columns, target); err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+// scanRow scans the current row into the value ptr points to
+func scanRow(rows *sql.Rows, columns []string, ptr reflect.Value) error {
+	target := ptr.Elem()
+
+	switch {
+	case isScalarType(target.Type()):
+		if len(columns) != 1 {
+			return fmt.Errorf("cannot scan %d columns into %s", len(columns), target.Type())
+		}
+		return rows.Scan(ptr.Interface())
+	case target.Kind() == reflect.Map:
+		return scanMap(rows, columns, target)
+	case target.Kind() == reflect.Struct:
+		return scanStruct(rows, columns, target)
+	default:
+		return fmt.Errorf("unsupported scan destination: %s", target.Type())
+	}
+}
+
+// scanStruct scans columns into struct fields matched by db tag
+func scanStruct(rows *sql.Rows, columns []string, target reflect.Value) error {
+	fields := structFieldMap(target.Type())
+	values := make([]interface{}, len(columns))
+
+	for i, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			index, ok = fields[strings.ToLower(column)]
+		}
+
+		if !ok {
+			// Discard columns that have no matching field
+			values[i] = new(interface{})
+			continue
+		}
+
+		values[i] = fieldByIndex(target, index).Addr().Interface()
+	}
+
+	return rows.Scan(values...)
+}
+
+// scanMap scans columns into a map keyed by column name
+func scanMap(rows *sql.Rows, columns []string, target reflect.Value) error {
+	mapType := target.Type()
+	if mapType.Key().Kind() != reflect.String || mapType.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("map destination must be map[string]interface{}, got %s", mapType)
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err := rows.Scan(pointers...); err != nil {
+		return err
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMapWithSize(mapType, len(columns)))
+	}
+
+	for i, column := range columns {
+		value := reflect.Zero(mapType.Elem())
+
+		switch v := values[i].(type) {
+		case nil:
+		case []byte:
+			value = reflect.ValueOf(string(v))
+		default:
+			value = reflect.ValueOf(v)
+		}
+
+		target.SetMapIndex(reflect.ValueOf(column).Convert(mapType.Key()), value)
+	}
+
+	return nil
+}
+
+// structFieldMap returns the cached column -> field index mapping for a struct type
+func structFieldMap(t reflect.Type) map[string][]int {
+	if cached, ok := fieldMapCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	fields := make(map[string][]int)
+	collectFields(t, nil, fields)
+
+	actual, _ := fieldMapCache.LoadOrStore(t, fields)
+	return actual.(map[string][]int)
+}
+
+// collectFields walks struct fields, descending into untagged embedded
+// structs such as Model. Shallower fields win over promoted ones.
+func collectFields(t reflect.Type, parent []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			tag = tag[:comma]
+		}
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && tag == "" && fieldType.Kind() == reflect.Struct && !isScalarType(fieldType) {
+			// Unexported embedded pointers cannot be allocated through reflection
+			if !field.IsExported() && field.Type.Kind() == reflect.Ptr {
+				continue
+			}
+			collectFields(fieldType, index, fields)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+
+		if existing, ok := fields[name]; ok && len(existing) <= len(index) {
+			continue
+		}
+		fields[name] = index
+	}
+}
+
+// fieldByIndex returns the nested field, allocating nil embedded pointers on the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v
+}
+
+// isScalarType reports whether values of t are scanned directly from a single column
+func isScalarType(t reflect.Type) bool {
+	if t == timeType || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return false
+	case reflect.Ptr:
+		return isScalarType(t.Elem())
+	default:
+		return true
+	}
+}
+
+// snakeCase converts a Go field name such as UserID to user_id
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var result strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					result.WriteByte('_')
+				}
+			}
+			result.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
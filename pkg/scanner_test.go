@@ -0,0 +1,326 @@
+package binigo
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubDriver answers each query with the canned result stored under the
+// query string, so the scanner can be tested without a database
+type stubDriver struct{}
+
+type stubConn struct{}
+
+type stubStmt struct{ query string }
+
+type stubResult struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type stubRows struct {
+	result *stubResult
+	next   int
+}
+
+var stubResults sync.Map
+
+func init() {
+	sql.Register("binigo-stub", stubDriver{})
+}
+
+func (stubDriver) Open(string) (driver.Conn, error) { return stubConn{}, nil }
+
+func (stubConn) Prepare(query string) (driver.Stmt, error) { return stubStmt{query}, nil }
+func (stubConn) Close() error                              { return nil }
+func (stubConn) Begin() (driver.Tx, error)                 { return nil, errors.New("stub: no transactions") }
+
+func (stubStmt) Close() error  { return nil }
+func (stubStmt) NumInput() int { return -1 }
+
+func (stubStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("stub: exec not supported")
+}
+
+func (s stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	result, ok := stubResults.Load(s.query)
+	if !ok {
+		return nil, errors.New("stub: unknown query " + s.query)
+	}
+	return &stubRows{result: result.(*stubResult)}, nil
+}
+
+func (r *stubRows) Columns() []string { return r.result.columns }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
+
+// queryStub returns rows that yield columns and values through the stub driver
+func queryStub(t *testing.T, columns []string, values [][]driver.Value) *sql.Rows {
+	t.Helper()
+
+	stubResults.Store(t.Name(), &stubResult{columns: columns, rows: values})
+
+	db, err := sql.Open("binigo-stub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Query(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		rows.Close()
+		db.Close()
+		stubResults.Delete(t.Name())
+	})
+	return rows
+}
+
+type scanUser struct {
+	ID       int64  `db:"id"`
+	FullName string `db:"name"`
+	UserID   int64
+	Email    *string
+	Nickname sql.NullString
+	Age      sql.NullInt64
+	Secret   string `db:"-"`
+}
+
+type ScanAudit struct {
+	CreatedBy string
+}
+
+type scanPost struct {
+	Model
+	*ScanAudit
+	Title string
+}
+
+type scanTitle struct {
+	Title string
+}
+
+func TestScan(t *testing.T) {
+	email := "ada@example.com"
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		columns []string
+		rows    [][]driver.Value
+		scan    func(*sql.Rows, interface{}) error
+		dest    interface{} // pointer the scan writes into
+		want    interface{} // expected value behind dest
+		err     string
+	}{
+		{
+			name:    "struct tags and snake_case",
+			columns: []string{"ID", "name", "user_id", "email", "nickname", "age", "secret", "unknown"},
+			rows: [][]driver.Value{
+				{int64(1), "Ada", int64(7), email, "ace", int64(36), "hidden", "x"},
+				{int64(2), []byte("Grace"), int64(8), nil, nil, nil, "hidden", "y"},
+			},
+			scan: scanRows,
+			dest: &[]scanUser{},
+			want: []scanUser{
+				{ID: 1, FullName: "Ada", UserID: 7, Email: &email,
+					Nickname: sql.NullString{String: "ace", Valid: true}, Age: sql.NullInt64{Int64: 36, Valid: true}},
+				{ID: 2, FullName: "Grace", UserID: 8},
+			},
+		},
+		{
+			name:    "embedded structs and pointers",
+			columns: []string{"id", "created_at", "created_by", "title"},
+			rows:    [][]driver.Value{{int64(3), createdAt, "ada", "Notes"}},
+			scan:    scanOne,
+			dest:    &scanPost{},
+			want: scanPost{
+				Model:     Model{ID: 3, CreatedAt: createdAt},
+				ScanAudit: &ScanAudit{CreatedBy: "ada"},
+				Title:     "Notes",
+			},
+		},
+		{
+			name:    "slice of struct pointers",
+			columns: []string{"title"},
+			rows:    [][]driver.Value{{"a"}, {"b"}},
+			scan:    scanRows,
+			dest:    &[]*scanTitle{},
+			want:    []*scanTitle{{Title: "a"}, {Title: "b"}},
+		},
+		{
+			name:    "pointer to a nil struct pointer",
+			columns: []string{"title"},
+			rows:    [][]driver.Value{{"a"}, {"b"}},
+			scan:    scanOne,
+			dest:    new(*scanTitle),
+			want:    &scanTitle{Title: "a"},
+		},
+		{
+			name:    "maps",
+			columns: []string{"id", "name", "deleted_at"},
+			rows:    [][]driver.Value{{int64(1), []byte("Ada"), nil}},
+			scan:    scanRows,
+			dest:    &[]Map{},
+			want:    []Map{{"id": int64(1), "name": "Ada", "deleted_at": nil}},
+		},
+		{
+			name:    "scalars",
+			columns: []string{"count"},
+			rows:    [][]driver.Value{{int64(4)}, {int64(5)}},
+			scan:    scanRows,
+			dest:    &[]int64{},
+			want:    []int64{4, 5},
+		},
+		{
+			name:    "nullable scalar",
+			columns: []string{"email"},
+			rows:    [][]driver.Value{{nil}},
+			scan:    scanOne,
+			dest:    new(*string),
+			want:    (*string)(nil),
+		},
+		{
+			name:    "no rows",
+			columns: []string{"title"},
+			scan:    scanRows,
+			dest:    &[]scanTitle{},
+			want:    []scanTitle{},
+		},
+
+		{
+			name:    "scalar with several columns",
+			columns: []string{"id", "name"},
+			rows:    [][]driver.Value{{int64(1), "Ada"}},
+			scan:    scanRows,
+			dest:    &[]int64{},
+			err:     "cannot scan 2 columns into int64",
+		},
+		{
+			name:    "NULL into a struct field",
+			columns: []string{"title"},
+			rows:    [][]driver.Value{{nil}},
+			scan:    scanOne,
+			dest:    &scanTitle{},
+			err:     "converting NULL to string is unsupported",
+		},
+		{
+			name:    "NULL into a scalar",
+			columns: []string{"title"},
+			rows:    [][]driver.Value{{nil}},
+			scan:    scanOne,
+			dest:    new(string),
+			err:     "converting NULL to string is unsupported",
+		},
+		{
+			name:    "map with the wrong value type",
+			columns: []string{"name"},
+			rows:    [][]driver.Value{{"Ada"}},
+			scan:    scanRows,
+			dest:    &[]map[string]string{},
+			err:     "map destination must be map[string]interface{}",
+		},
+		{
+			name:    "unsupported element",
+			columns: []string{"title"},
+			rows:    [][]driver.Value{{"a"}},
+			scan:    scanRows,
+			dest:    &[]**scanTitle{},
+			err:     "unsupported scan destination",
+		},
+		{
+			name:    "non-pointer destination",
+			columns: []string{"title"},
+			scan:    scanRows,
+			dest:    []scanTitle{},
+			err:     "dest must be a pointer",
+		},
+		{
+			name:    "pointer to a non-slice",
+			columns: []string{"title"},
+			scan:    scanRows,
+			dest:    &scanTitle{},
+			err:     "dest must be a pointer to a slice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scan(queryStub(t, tt.columns, tt.rows), tt.dest)
+
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := reflect.ValueOf(tt.dest).Elem().Interface(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScanOneWithoutRows(t *testing.T) {
+	var title scanTitle
+	err := scanOne(queryStub(t, []string{"title"}, nil), &title)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestStructFieldMapIsCached(t *testing.T) {
+	typ := reflect.TypeOf(scanPost{})
+	first := structFieldMap(typ)
+
+	want := map[string][]int{
+		"id":         {0, 0},
+		"created_at": {0, 1},
+		"updated_at": {0, 2},
+		"created_by": {1, 0},
+		"title":      {2},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("expected fields %v, got %v", want, first)
+	}
+
+	if cached, ok := fieldMapCache.Load(typ); !ok || reflect.ValueOf(cached).Pointer() != reflect.ValueOf(first).Pointer() {
+		t.Fatal("expected the field map to be cached")
+	}
+	if reflect.ValueOf(structFieldMap(typ)).Pointer() != reflect.ValueOf(first).Pointer() {
+		t.Fatal("expected the cached field map to be reused")
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"ID":           "id",
+		"UserID":       "user_id",
+		"CreatedAt":    "created_at",
+		"HTTPServer":   "http_server",
+		"Address2Line": "address2_line",
+		"name":         "name",
+	} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q): expected %q, got %q", name, want, got)
+		}
+	}
+}
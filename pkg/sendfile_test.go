@@ -0,0 +1,87 @@
+package binigo_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+)
+
+func TestSendFileRangesAndConditionals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alphabet.txt")
+	if err := os.WriteFile(path, []byte("abcdefghijklmnopqrstuvwxyz"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	lastModified := modTime.Format(http.TimeFormat)
+
+	app := binigo.NewApplication(nil)
+	app.Get("/file", func(ctx *binigo.Context) error {
+		return ctx.SendFile(path)
+	})
+	ta := binigotest.New(t, app)
+
+	full := ta.Test().Get("/file").Expect(200).
+		Header("Accept-Ranges", "bytes").
+		Header("Last-Modified", lastModified)
+	if body := string(full.Body()); body != "abcdefghijklmnopqrstuvwxyz" {
+		t.Fatalf("expected the whole file, got %q", body)
+	}
+	etag := string(full.Raw().Header.Peek("ETag"))
+	if etag == "" {
+		t.Fatal("expected an ETag")
+	}
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"single range", map[string]string{"Range": "bytes=2-5"}, 206, "cdef", "bytes 2-5/26"},
+		{"open range", map[string]string{"Range": "bytes=23-"}, 206, "xyz", "bytes 23-25/26"},
+		{"suffix range", map[string]string{"Range": "bytes=-2"}, 206, "yz", "bytes 24-25/26"},
+		{"range past the end", map[string]string{"Range": "bytes=20-99"}, 206, "uvwxyz", "bytes 20-25/26"},
+		{"unsatisfiable range", map[string]string{"Range": "bytes=26-"}, 416, "", "bytes */26"},
+		{"multiple ranges", map[string]string{"Range": "bytes=0-1,4-5"}, 200, "abcdefghijklmnopqrstuvwxyz", ""},
+
+		{"matching If-None-Match", map[string]string{"If-None-Match": `"other", ` + etag}, 304, "", ""},
+		{"stale If-None-Match", map[string]string{"If-None-Match": `"other"`}, 200, "abcdefghijklmnopqrstuvwxyz", ""},
+		{"If-Modified-Since at the modification time", map[string]string{"If-Modified-Since": lastModified}, 304, "", ""},
+		{"If-Modified-Since before the modification time",
+			map[string]string{"If-Modified-Since": "Mon, 01 Jan 2024 00:00:00 GMT"}, 200, "abcdefghijklmnopqrstuvwxyz", ""},
+		{"If-None-Match wins over If-Modified-Since",
+			map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": lastModified}, 200, "abcdefghijklmnopqrstuvwxyz", ""},
+
+		{"If-Range with the current ETag", map[string]string{"Range": "bytes=0-2", "If-Range": etag}, 206, "abc", "bytes 0-2/26"},
+		{"If-Range with a stale ETag", map[string]string{"Range": "bytes=0-2", "If-Range": `"stale"`}, 200, "abcdefghijklmnopqrstuvwxyz", ""},
+		{"If-Range with the modification time", map[string]string{"Range": "bytes=0-2", "If-Range": lastModified}, 206, "abc", "bytes 0-2/26"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := binigotest.New(t, app).Test().Get("/file")
+			for name, value := range tt.headers {
+				req.Header(name, value)
+			}
+
+			resp := req.Expect(tt.status)
+			if tt.status == 416 {
+				resp.JSONPath("code", "range_not_satisfiable")
+			} else if body := string(resp.Body()); body != tt.body {
+				t.Fatalf("expected body %q, got %q", tt.body, body)
+			}
+			if got := string(resp.Raw().Header.Peek("Content-Range")); got != tt.contentRange {
+				t.Fatalf("expected Content-Range %q, got %q", tt.contentRange, got)
+			}
+		})
+	}
+}
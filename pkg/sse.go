@@ -0,0 +1,239 @@
+package binigo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSSEHeartbeat is how often an idle event stream sends a comment
+// line to keep proxies from closing the connection
+const DefaultSSEHeartbeat = 15 * time.Second
+
+// ErrStreamClosed is returned by SSEStream methods once the client has
+// disconnected or the stream has ended
+var ErrStreamClosed = errors.New("sse: stream closed")
+
+// SSEStream writes Server-Sent Events to a client. Its methods are safe to
+// call from multiple goroutines.
+type SSEStream struct {
+	w           *bufio.Writer
+	lastEventID string
+	json        JSONCodec
+	ctx         context.Context
+	cancel      context.CancelFunc
+	heartbeat   *time.Ticker
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// SSE streams Server-Sent Events to the client. fn runs after the handler
+// returns, once the response headers are sent, so it must not use the
+// Context; copy anything it needs from the request beforehand. fn should
+// return when stream.Done() is closed, which happens when the client
+// disconnects or the server shuts down.
+//
+//	return ctx.SSE(func(stream *binigo.SSEStream) error {
+//		for progress := range job.Progress(stream.LastEventID()) {
+//			if err := stream.Send("progress", progress.ID, progress); err != nil {
+//				return err
+//			}
+//		}
+//		return nil
+//	})
+func (c *Context) SSE(fn func(stream *SSEStream) error) error {
+	lastEventID := c.Header("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource polyfills that cannot set headers send it in the query
+		lastEventID = c.Query("lastEventId")
+	}
+
+	conn := c.fastCtx.Conn()
+	app := c.app
+	codec := c.jsonCodec()
+
+	c.fastCtx.SetContentType("text/event-stream")
+	c.fastCtx.Response.Header.Set("Cache-Control", "no-cache")
+	c.fastCtx.Response.Header.Set("X-Accel-Buffering", "no")
+	c.fastCtx.SetStatusCode(200)
+
+	c.fastCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stopDisconnect := watchDisconnect(conn, cancel)
+		defer stopDisconnect()
+
+		if app != nil {
+			stopShutdown := context.AfterFunc(app.shutdownContext(), cancel)
+			defer stopShutdown()
+		}
+
+		stream := &SSEStream{
+			w:           w,
+			lastEventID: lastEventID,
+			json:        codec,
+			ctx:         ctx,
+			cancel:      cancel,
+			heartbeat:   time.NewTicker(DefaultSSEHeartbeat),
+		}
+		defer stream.close()
+
+		// Send the headers right away so the client sees the stream open
+		if err := stream.flush(); err != nil {
+			return
+		}
+
+		go stream.keepAlive()
+
+		if err := fn(stream); err != nil && !errors.Is(err, ErrStreamClosed) {
+			log.Printf("SSE stream error: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// LastEventID returns the ID the client reported when reconnecting, so the
+// producer can resume after it. It is empty on the first connection.
+func (s *SSEStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Context returns a context that is cancelled when the stream ends
+func (s *SSEStream) Context() context.Context {
+	return s.ctx
+}
+
+// Done is closed when the client disconnects or the server shuts down
+func (s *SSEStream) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+// Send writes an event. event and id are omitted when empty. Strings and
+// byte slices are sent as is; other data is encoded as JSON.
+func (s *SSEStream) Send(event, id string, data interface{}) error {
+	var payload string
+	switch value := data.(type) {
+	case string:
+		payload = value
+	case []byte:
+		payload = string(value)
+	default:
+		encoded, err := s.json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		payload = string(encoded)
+	}
+
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + stripNewlines(id) + "\n")
+	}
+	if event != "" {
+		b.WriteString("event: " + stripNewlines(event) + "\n")
+	}
+	for _, line := range strings.Split(payload, "\n") {
+		b.WriteString("data: " + strings.TrimSuffix(line, "\r") + "\n")
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Retry tells the client how long to wait before reconnecting
+func (s *SSEStream) Retry(delay time.Duration) error {
+	return s.write("retry: " + strconv.FormatInt(delay.Milliseconds(), 10) + "\n\n")
+}
+
+// Comment writes a comment line, which clients ignore
+func (s *SSEStream) Comment(text string) error {
+	return s.write(": " + stripNewlines(text) + "\n\n")
+}
+
+// Heartbeat changes how often comment lines are sent to keep the
+// connection open. Zero or a negative interval disables them.
+func (s *SSEStream) Heartbeat(interval time.Duration) {
+	if interval <= 0 {
+		s.heartbeat.Stop()
+		return
+	}
+	s.heartbeat.Reset(interval)
+}
+
+// keepAlive sends heartbeat comments until the stream ends. A failed write
+// marks the stream closed so the producer stops.
+func (s *SSEStream) keepAlive() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.heartbeat.C:
+			if s.Comment("heartbeat") != nil {
+				return
+			}
+		}
+	}
+}
+
+// write sends raw event stream data and flushes it to the client
+func (s *SSEStream) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || s.ctx.Err() != nil {
+		return ErrStreamClosed
+	}
+
+	if _, err := s.w.WriteString(data); err != nil {
+		s.closeLocked()
+		return ErrStreamClosed
+	}
+	if err := s.w.Flush(); err != nil {
+		s.closeLocked()
+		return ErrStreamClosed
+	}
+	return nil
+}
+
+// flush sends buffered output, closing the stream on failure
+func (s *SSEStream) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		s.closeLocked()
+		return ErrStreamClosed
+	}
+	return nil
+}
+
+// close ends the stream; writes after close fail with ErrStreamClosed
+func (s *SSEStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *SSEStream) closeLocked() {
+	if !s.closed {
+		s.closed = true
+		s.heartbeat.Stop()
+		s.cancel()
+	}
+}
+
+// newlineStripper removes line breaks from single-line SSE fields
+var newlineStripper = strings.NewReplacer("\r", "", "\n", "")
+
+// stripNewlines keeps single-line SSE fields from breaking the framing
+func stripNewlines(s string) string {
+	return newlineStripper.Replace(s)
+}
@@ -0,0 +1,197 @@
+package binigo
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// StaticOptions configures Static. The zero value serves files without
+// directory indexes, compression or caching headers.
+type StaticOptions struct {
+	FS         fs.FS         // serve dir from this file system, e.g. an embed.FS, instead of the disk
+	Index      bool          // serve index.html for directory requests
+	Browse     bool          // list directories that have no index.html
+	Compress   bool          // serve precompressed .br and .gz files, compressing others in memory
+	ByteRange  bool          // honour Range requests
+	MaxAge     time.Duration // Cache-Control max-age for files; zero sends no Cache-Control
+	Immutable  bool          // mark cached files immutable, for fingerprinted asset names
+	SPA        bool          // serve index.html for unmatched paths without a file extension
+	SPAExclude []string      // path prefixes that never fall back to index.html; defaults to "/api"
+}
+
+// staticParam is the route parameter holding the requested file name
+const staticParam = "path"
+
+// staticPathKey is the user value carrying the file path for fasthttp.FS
+const staticPathKey = "binigo.staticPath"
+
+// staticNotFoundKey is set when fasthttp.FS cannot find the file
+const staticNotFoundKey = "binigo.staticNotFound"
+
+// Static serves the files in dir under prefix using fasthttp.FS. It is a
+// fallback rather than a route: it only sees GET and HEAD requests that no
+// route matches, and a missing file gets the usual 404 or 405 response, so
+// Static("/", "public") can sit alongside the application's routes. The
+// file name is available to middleware as Param("path"). With FS set, dir
+// is a directory within it:
+//
+//	//go:embed public
+//	var assets embed.FS
+//
+//	app.Static("/", "public", binigo.StaticOptions{FS: assets, SPA: true})
+func (r *Router) Static(prefix, dir string, options ...StaticOptions) *Route {
+	var opts StaticOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.SPAExclude == nil {
+		opts.SPAExclude = []string{"/api"}
+	}
+
+	fsys := opts.FS
+	if fsys == nil {
+		// Reading through fs.FS keeps fasthttp from writing compressed
+		// copies next to the originals
+		fsys = os.DirFS(dir)
+	} else if dir != "" && dir != "." {
+		sub, err := fs.Sub(fsys, strings.Trim(dir, "/"))
+		if err != nil {
+			panic("binigo: static " + dir + ": " + err.Error())
+		}
+		fsys = sub
+	}
+
+	handler := newStaticHandler(fsys, opts)
+
+	route := &Route{
+		method:  fasthttp.MethodGet,
+		path:    r.prefix + strings.TrimSuffix(prefix, "/") + "/{" + staticParam + "...}",
+		handler: func(ctx *Context) error { return handler(ctx, ctx.Param(staticParam)) },
+		group:   r,
+	}
+	route.compile()
+
+	r.table.update(func() {
+		r.table.statics = append(r.table.statics, route)
+	})
+	return route
+}
+
+// Static serves the files in dir under prefix. See Router.Static.
+func (a *Application) Static(prefix, dir string, options ...StaticOptions) *Route {
+	return a.router.Static(prefix, dir, options...)
+}
+
+// newStaticHandler returns a handler serving name from fsys
+func newStaticHandler(fsys fs.FS, opts StaticOptions) func(ctx *Context, name string) error {
+	server := &fasthttp.FS{
+		FS:                 fsys,
+		AllowEmptyRoot:     true,
+		GenerateIndexPages: opts.Browse,
+		Compress:           opts.Compress,
+		CompressBrotli:     opts.Compress,
+		AcceptByteRange:    opts.ByteRange,
+		CompressedFileSuffixes: map[string]string{
+			"gzip": ".gz",
+			"br":   ".br",
+			"zstd": ".zst",
+		},
+		PathRewrite: func(ctx *fasthttp.RequestCtx) []byte {
+			return []byte(ctx.UserValue(staticPathKey).(string))
+		},
+		PathNotFound: func(ctx *fasthttp.RequestCtx) {
+			ctx.SetUserValue(staticNotFoundKey, true)
+		},
+	}
+	if opts.Index || opts.SPA {
+		server.IndexNames = []string{"index.html"}
+	}
+	serve := server.NewRequestHandler()
+
+	cacheControl := ""
+	if opts.MaxAge > 0 {
+		cacheControl = "public, max-age=" + strconv.Itoa(int(opts.MaxAge.Seconds()))
+		if opts.Immutable {
+			cacheControl += ", immutable"
+		}
+	}
+
+	return func(ctx *Context, name string) error {
+		filePath := "/" + name
+		fallback := false
+
+		switch {
+		case opts.SPA && spaFallback(fsys, ctx.Path(), name, opts.SPAExclude):
+			filePath, fallback = "/index.html", true
+		case !staticExists(fsys, name):
+			// Answer misses here; fasthttp.FS logs every file it cannot open
+			return NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found")
+		}
+
+		ctx.fastCtx.SetUserValue(staticPathKey, filePath)
+		serve(ctx.fastCtx)
+
+		if ctx.fastCtx.UserValue(staticNotFoundKey) != nil {
+			ctx.fastCtx.RemoveUserValue(staticNotFoundKey)
+			return NewHTTPError(fasthttp.StatusNotFound).WithCode("not_found")
+		}
+
+		header := &ctx.fastCtx.Response.Header
+		switch header.StatusCode() {
+		case fasthttp.StatusOK, fasthttp.StatusPartialContent, fasthttp.StatusNotModified:
+		case fasthttp.StatusForbidden:
+			// fasthttp refuses directories without an index page
+			return NewHTTPError(fasthttp.StatusForbidden).WithCode("forbidden")
+		default:
+			return nil
+		}
+
+		// Embedded files have no modification time
+		if lastModified, err := fasthttp.ParseHTTPDate(header.Peek("Last-Modified")); err == nil && lastModified.Year() <= 1 {
+			header.Del("Last-Modified")
+		}
+
+		switch {
+		case fallback || strings.HasSuffix(filePath, "/") || path.Base(filePath) == "index.html":
+			// Pages must be revalidated so new deploys are picked up
+			ctx.SetHeader("Cache-Control", "no-cache")
+		case cacheControl != "":
+			ctx.SetHeader("Cache-Control", cacheControl)
+		}
+		return nil
+	}
+}
+
+// spaFallback reports whether a request for name should get the SPA's
+// index.html: the file does not exist, the path looks like a page rather
+// than an asset, and it is not under an excluded prefix such as /api
+func spaFallback(fsys fs.FS, requestPath, name string, exclude []string) bool {
+	if name == "" || path.Ext(name) != "" {
+		return false
+	}
+
+	for _, prefix := range exclude {
+		if requestPath == prefix || strings.HasPrefix(requestPath, strings.TrimSuffix(prefix, "/")+"/") {
+			return false
+		}
+	}
+
+	_, err := fs.Stat(fsys, strings.TrimSuffix(name, "/"))
+	return err != nil
+}
+
+// staticExists reports whether name, as requested, exists in fsys
+func staticExists(fsys fs.FS, name string) bool {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
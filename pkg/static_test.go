@@ -0,0 +1,81 @@
+package binigo_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+)
+
+var publicFS = fstest.MapFS{
+	"public/index.html":      {Data: []byte("<h1>home</h1>")},
+	"public/css/app.css":     {Data: []byte("body{}")},
+	"public/docs/index.html": {Data: []byte("<h1>docs</h1>")},
+	"vendor/lib.js":          {Data: []byte("lib()")},
+}
+
+func TestStaticFallsBackAfterRoutes(t *testing.T) {
+	app := binigo.NewApplication(nil)
+	app.Get("/css/app.css", func(ctx *binigo.Context) error {
+		return ctx.String("route")
+	})
+	app.Post("/users", func(ctx *binigo.Context) error {
+		return ctx.Status(201).String("created")
+	})
+	app.Static("/", "public", binigo.StaticOptions{FS: publicFS, Index: true})
+
+	ta := binigotest.New(t, app)
+
+	// Routes win over files and the static mount adds no methods
+	ta.Test().Get("/css/app.css").Expect(200).Contains("route")
+	ta.Test().Get("/docs/").Expect(200).Contains("docs").Header("Cache-Control", "no-cache")
+	ta.Test().Get("/").Expect(200).Contains("home")
+	ta.Test().Post("/users").Expect(201)
+	ta.Test().Get("/users").Expect(405).Header("Allow", "OPTIONS, POST")
+	ta.Test().Options("/users").Expect(204).Header("Allow", "OPTIONS, POST")
+
+	// Unmatched paths keep their usual responses
+	ta.Test().Post("/nope").Expect(404).JSONPath("code", "not_found")
+	ta.Test().Options("/totally/unknown").Expect(404)
+	ta.Test().Get("/missing.css").Expect(404).JSONPath("code", "not_found")
+	ta.Test().Delete("/index.html").Expect(404)
+
+	if body := ta.Test().Head("/index.html").Expect(200).Body(); len(body) != 0 {
+		t.Fatalf("expected HEAD to send no body, got %q", body)
+	}
+}
+
+func TestStaticMounts(t *testing.T) {
+	app := binigo.NewApplication(nil)
+	app.Static("/", "public", binigo.StaticOptions{FS: publicFS, SPA: true})
+	app.Static("/vendor", "vendor", binigo.StaticOptions{FS: publicFS, MaxAge: 3600e9, Immutable: true})
+
+	admin := app.Group("/admin", nil).Middleware(func(next binigo.HandlerFunc) binigo.HandlerFunc {
+		return func(ctx *binigo.Context) error {
+			ctx.SetHeader("X-Admin", ctx.Param("path"))
+			return next(ctx)
+		}
+	})
+	admin.Static("/assets", "public/css", binigo.StaticOptions{FS: publicFS}).Name("admin.assets")
+
+	ta := binigotest.New(t, app)
+
+	// The longest matching prefix is tried first
+	ta.Test().Get("/vendor/lib.js").Expect(200).
+		Contains("lib()").
+		Header("Cache-Control", "public, max-age=3600, immutable")
+
+	// SPA pages fall back to index.html, assets and the API do not
+	ta.Test().Get("/dashboard/settings").Expect(200).Contains("home").Header("Cache-Control", "no-cache")
+	ta.Test().Get("/missing.js").Expect(404)
+	ta.Test().Get("/api/users").Expect(404).JSONPath("code", "not_found")
+
+	// Group mounts get the group's prefix and middleware
+	ta.Test().Get("/admin/assets/app.css").Expect(200).Contains("body{}").Header("X-Admin", "app.css")
+
+	url, err := app.Router().URL("admin.assets", binigo.Map{"path": "app.css"})
+	if err != nil || url != "/admin/assets/app.css" {
+		t.Fatalf("expected /admin/assets/app.css, got %q (%v)", url, err)
+	}
+}
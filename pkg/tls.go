@@ -0,0 +1,121 @@
+package binigo
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// certCheckInterval limits how often certificate files are checked for changes
+const certCheckInterval = 5 * time.Second
+
+// certReloader serves a certificate loaded from disk and reloads it when
+// the certificate or key file changes, so renewed certificates are picked
+// up without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	certMod   time.Time
+	keyMod    time.Time
+	lastCheck time.Time
+}
+
+// newCertReloader loads the certificate pair, failing if it cannot be read
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads the certificate pair and records the file modification times
+func (r *certReloader) load() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// modTimes returns the modification times of the certificate and key files
+func (r *certReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("stat certificate: %w", err)
+	}
+
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("stat key: %w", err)
+	}
+
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate. It checks the files
+// at most once per certCheckInterval and keeps serving the previous
+// certificate if a reload fails.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	cert := r.cert
+	due := time.Since(r.lastCheck) >= certCheckInterval
+	r.mu.RUnlock()
+
+	if !due {
+		return cert, nil
+	}
+
+	r.mu.Lock()
+	r.lastCheck = time.Now()
+	certMod, keyMod := r.certMod, r.keyMod
+	r.mu.Unlock()
+
+	newCertMod, newKeyMod, err := r.modTimes()
+	if err != nil || (newCertMod.Equal(certMod) && newKeyMod.Equal(keyMod)) {
+		return cert, nil
+	}
+
+	if err := r.load(); err != nil {
+		log.Printf("TLS certificate reload failed, keeping current certificate: %v", err)
+		return cert, nil
+	}
+
+	log.Printf("TLS certificate reloaded from %s", r.certFile)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// newTLSConfig builds a TLS configuration that reloads certFile and keyFile on change
+func newTLSConfig(certFile, keyFile string) (*tls.Config, error) {
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}, nil
+}
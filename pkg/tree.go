@@ -0,0 +1,463 @@
+package binigo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// segmentKind identifies how a piece of a route path is matched
+type segmentKind uint8
+
+const (
+	staticSegment segmentKind = iota
+	paramSegment
+	catchAllSegment
+)
+
+// routeSegment is one parsed piece of a route path: literal text, a
+// {param}/{param?} placeholder or a trailing {param...} catch-all
+type routeSegment struct {
+	kind       segmentKind
+	text       string // literal text for static segments
+	name       string // parameter name for param and catch-all segments
+	optional   bool
+	constraint *paramConstraint
+}
+
+// paramConstraint restricts the values a route parameter accepts
+type paramConstraint struct {
+	pattern string
+	match   func(string) bool
+}
+
+// namedConstraints are the built-in constraints usable as {id:int}
+var namedConstraints = map[string]func(string) bool{
+	"int":       isDigits,
+	"alpha":     isAlpha,
+	"alpha_num": isAlphaNum,
+	"uuid":      isUUID,
+}
+
+// constraintCache shares compiled constraints between routes
+var constraintCache sync.Map
+
+// newParamConstraint resolves a named constraint or compiles pattern as a
+// regular expression that must match the whole parameter value
+func newParamConstraint(pattern string) (*paramConstraint, error) {
+	if cached, ok := constraintCache.Load(pattern); ok {
+		return cached.(*paramConstraint), nil
+	}
+
+	constraint := &paramConstraint{pattern: pattern}
+	if fn, ok := namedConstraints[pattern]; ok {
+		constraint.match = fn
+	} else {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %v", pattern, err)
+		}
+		constraint.match = re.MatchString
+	}
+
+	actual, _ := constraintCache.LoadOrStore(pattern, constraint)
+	return actual.(*paramConstraint), nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaNum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isAlpha(s[i:i+1]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			c := s[i]
+			if !(c >= '0' && c <= '9') && !(c|0x20 >= 'a' && c|0x20 <= 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseRoutePath splits a route path into static and parameter segments.
+// Parameters must occupy a whole path segment and a catch-all must come last.
+// A parameter may carry a constraint after a colon: {id:int}, {slug:[a-z-]+}.
+// Modifiers go before the colon, as in {id?:int} and {path...:[a-z/]+}.
+func parseRoutePath(path string) ([]routeSegment, error) {
+	segments := make([]routeSegment, 0, 4)
+
+	for len(path) > 0 {
+		open := strings.IndexByte(path, '{')
+		if open < 0 {
+			segments = append(segments, routeSegment{kind: staticSegment, text: path})
+			break
+		}
+
+		if open > 0 {
+			segments = append(segments, routeSegment{kind: staticSegment, text: path[:open]})
+		}
+		if open == 0 || path[open-1] != '/' {
+			return nil, fmt.Errorf("parameter must start a path segment")
+		}
+
+		end := closingBrace(path, open)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated parameter")
+		}
+
+		segment := routeSegment{kind: paramSegment, name: path[open+1 : end]}
+		if colon := strings.IndexByte(segment.name, ':'); colon >= 0 {
+			pattern := segment.name[colon+1:]
+			if strings.HasSuffix(pattern, "?") {
+				// {id:int?} would otherwise compile as the expression "int?"
+				return nil, fmt.Errorf("constraint %q ends in \"?\", use {%s?:%s} for an optional parameter",
+					pattern, segment.name[:colon], strings.TrimSuffix(pattern, "?"))
+			}
+			constraint, err := newParamConstraint(pattern)
+			if err != nil {
+				return nil, err
+			}
+			segment.constraint = constraint
+			segment.name = segment.name[:colon]
+		}
+
+		switch {
+		case strings.HasSuffix(segment.name, "..."):
+			segment.kind = catchAllSegment
+			segment.name = strings.TrimSuffix(segment.name, "...")
+		case strings.HasSuffix(segment.name, "?"):
+			segment.optional = true
+			segment.name = strings.TrimSuffix(segment.name, "?")
+		}
+
+		if segment.name == "" {
+			return nil, fmt.Errorf("parameter name is empty")
+		}
+
+		path = path[end+1:]
+		if len(path) > 0 && path[0] != '/' {
+			return nil, fmt.Errorf("parameter {%s} must end a path segment", segment.name)
+		}
+		if segment.kind == catchAllSegment && len(path) > 0 {
+			return nil, fmt.Errorf("catch-all {%s...} must be the last segment", segment.name)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	// Optional parameters may only be followed by other optional parameters
+	seenOptional := false
+	for _, segment := range segments {
+		switch {
+		case segment.optional:
+			seenOptional = true
+		case seenOptional && segment.kind != staticSegment:
+			return nil, fmt.Errorf("required parameter {%s} follows an optional one", segment.name)
+		case seenOptional && segment.text != "/":
+			return nil, fmt.Errorf("static text %q follows an optional parameter", segment.text)
+		}
+	}
+
+	return segments, nil
+}
+
+// closingBrace finds the brace closing the one at open, allowing nested
+// braces inside constraint patterns such as {code:[0-9]{3}}
+func closingBrace(path string, open int) int {
+	depth := 0
+	for i := open; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expandOptional returns every concrete variant of a route: the full path
+// and one variant per trailing optional parameter dropped
+func expandOptional(segments []routeSegment) [][]routeSegment {
+	variants := [][]routeSegment{segments}
+
+	current := segments
+	for len(current) > 0 {
+		last := current[len(current)-1]
+		if last.kind == staticSegment && last.text == "/" && len(current) > 1 {
+			last = current[len(current)-2]
+			current = current[:len(current)-1]
+		}
+		if !last.optional {
+			break
+		}
+
+		// Drop the parameter and the slash that introduced it
+		current = current[:len(current)-1]
+		variant := make([]routeSegment, len(current))
+		copy(variant, current)
+
+		if n := len(variant); n > 0 && variant[n-1].kind == staticSegment {
+			text := strings.TrimSuffix(variant[n-1].text, "/")
+			if text == "" {
+				variant = variant[:n-1]
+			} else {
+				variant[n-1].text = text
+			}
+		}
+		if len(variant) == 0 {
+			variant = []routeSegment{{kind: staticSegment, text: "/"}}
+		}
+
+		variants = append(variants, variant)
+		current = variant
+	}
+
+	return variants
+}
+
+// routeLeaf terminates a tree path. Parameter names live on the leaf so
+// routes sharing a parameter position may name it differently.
+type routeLeaf struct {
+	route      *Route
+	handler    HandlerFunc   // route handler wrapped in its middleware chain
+	timeout    time.Duration // copied when compiled so Timeout can change it while serving
+	paramNames []string
+}
+
+// node is a compressed radix tree node. Static children are tried before
+// parameter children, which are tried before catch-alls. Constrained
+// parameters are tried before unconstrained ones at the same position.
+type node struct {
+	kind       segmentKind
+	prefix     string
+	indices    []byte
+	statics    []*node
+	params     []*node
+	catchAlls  []*node
+	constraint *paramConstraint
+	leaf       *routeLeaf
+}
+
+// insert adds a route variant below n
+func (n *node) insert(segments []routeSegment, route *Route, handler HandlerFunc) {
+	paramNames := make([]string, 0, len(segments))
+	current := n
+
+	for _, segment := range segments {
+		switch segment.kind {
+		case staticSegment:
+			current = current.insertStatic(segment.text)
+		case paramSegment:
+			current = dynamicChild(&current.params, segment)
+			paramNames = append(paramNames, segment.name)
+		case catchAllSegment:
+			current = dynamicChild(&current.catchAlls, segment)
+			paramNames = append(paramNames, segment.name)
+		}
+	}
+
+	// The first registration wins, matching the previous linear scan
+	if current.leaf == nil {
+		current.leaf = &routeLeaf{route: route, handler: handler, timeout: route.timeout, paramNames: paramNames}
+	}
+}
+
+// dynamicChild returns the child in children matching the segment's
+// constraint, creating it ahead of any unconstrained sibling if missing
+func dynamicChild(children *[]*node, segment routeSegment) *node {
+	for _, child := range *children {
+		if child.constraint == segment.constraint {
+			return child
+		}
+	}
+
+	child := &node{kind: segment.kind, constraint: segment.constraint}
+	list := *children
+
+	if n := len(list); segment.constraint != nil && n > 0 && list[n-1].constraint == nil {
+		list = append(list[:n-1], child, list[n-1])
+	} else {
+		list = append(list, child)
+	}
+
+	*children = list
+	return child
+}
+
+// insertStatic walks or splits static children so that path ends at a node
+func (n *node) insertStatic(path string) *node {
+	current := n
+
+walk:
+	for path != "" {
+		for i, child := range current.statics {
+			common := commonPrefix(path, child.prefix)
+			if common == 0 {
+				continue
+			}
+
+			if common < len(child.prefix) {
+				// Split the child so the shared prefix becomes its own node
+				split := &node{
+					kind:    staticSegment,
+					prefix:  child.prefix[:common],
+					indices: []byte{child.prefix[common]},
+					statics: []*node{child},
+				}
+				child.prefix = child.prefix[common:]
+				current.statics[i] = split
+				child = split
+			}
+
+			path = path[common:]
+			current = child
+			continue walk
+		}
+
+		child := &node{kind: staticSegment, prefix: path}
+		current.indices = append(current.indices, path[0])
+		current.statics = append(current.statics, child)
+		return child
+	}
+
+	return current
+}
+
+// match finds the leaf for path, appending parameter values in order.
+// values is left untouched on static routes so those lookups never allocate.
+func (n *node) match(path string, values *[]string) *routeLeaf {
+	switch n.kind {
+	case staticSegment:
+		if len(path) < len(n.prefix) || path[:len(n.prefix)] != n.prefix {
+			return nil
+		}
+		return n.matchChildren(path[len(n.prefix):], values)
+	case paramSegment:
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end == 0 {
+			return nil
+		}
+		if n.constraint != nil && !n.constraint.match(path[:end]) {
+			return nil
+		}
+
+		*values = append(*values, path[:end])
+		if leaf := n.matchChildren(path[end:], values); leaf != nil {
+			return leaf
+		}
+		*values = (*values)[:len(*values)-1]
+		return nil
+	default:
+		if n.constraint != nil && !n.constraint.match(path) {
+			return nil
+		}
+
+		*values = append(*values, path)
+		return n.leaf
+	}
+}
+
+// matchChildren matches the remainder of path against n's children
+func (n *node) matchChildren(path string, values *[]string) *routeLeaf {
+	if path == "" && n.leaf != nil {
+		return n.leaf
+	}
+
+	if path != "" {
+		first := path[0]
+		for i, c := range n.indices {
+			if c != first {
+				continue
+			}
+			if leaf := n.statics[i].match(path, values); leaf != nil {
+				return leaf
+			}
+			break
+		}
+
+		for _, child := range n.params {
+			if leaf := child.match(path, values); leaf != nil {
+				return leaf
+			}
+		}
+	}
+
+	for _, child := range n.catchAlls {
+		if leaf := child.match(path, values); leaf != nil {
+			return leaf
+		}
+	}
+
+	return nil
+}
+
+// commonPrefix returns the length of the shared prefix of a and b
+func commonPrefix(a, b string) int {
+	max := len(a)
+	if len(b) < max {
+		max = len(b)
+	}
+
+	i := 0
+	for i < max && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// bytesToString converts without copying. The result must not outlive b.
+func bytesToString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
@@ -0,0 +1,266 @@
+package binigo
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func noopHandler(ctx *Context) error { return nil }
+
+func TestTreeMatch(t *testing.T) {
+	r := NewRouter()
+	for _, path := range []string{
+		"/",
+		"/users",
+		"/users/new",
+		"/users/{id}",
+		"/users/{id}/posts/{post}",
+		"/orders/{id:int}",
+		"/orders/{slug}",
+		"/teams/{id:int}/members",
+		"/teams/{name}/settings",
+		"/posts/{year}/{slug?}",
+		"/pages/{id?:int}",
+		"/files/{path...}",
+		"/archive/{path...:[a-z/]+}",
+		"/archive/{rest...}",
+		"/search",
+		"/search/{q}",
+	} {
+		r.Get(path, noopHandler)
+	}
+
+	tests := []struct {
+		path   string
+		route  string // registered path, empty for no match
+		params map[string]string
+	}{
+		{"/", "/", nil},
+		{"/users", "/users", nil},
+		{"/users/", "", nil},
+		{"/users/new", "/users/new", nil},
+		{"/users/newest", "/users/{id}", map[string]string{"id": "newest"}},
+		{"/users/42", "/users/{id}", map[string]string{"id": "42"}},
+		{"/users/42/posts/7", "/users/{id}/posts/{post}", map[string]string{"id": "42", "post": "7"}},
+		{"/users/42/posts", "", nil},
+		{"/users//posts/7", "", nil},
+
+		// Constrained parameters are tried first, then their siblings
+		{"/orders/12", "/orders/{id:int}", map[string]string{"id": "12"}},
+		{"/orders/latest", "/orders/{slug}", map[string]string{"slug": "latest"}},
+
+		// Backtracking after the constrained branch fails deeper down
+		{"/teams/12/members", "/teams/{id:int}/members", map[string]string{"id": "12"}},
+		{"/teams/12/settings", "/teams/{name}/settings", map[string]string{"name": "12"}},
+		{"/teams/core/members", "", nil},
+
+		// Optional parameters expand into one route per variant
+		{"/posts/2024/hello", "/posts/{year}/{slug?}", map[string]string{"year": "2024", "slug": "hello"}},
+		{"/posts/2024", "/posts/{year}/{slug?}", map[string]string{"year": "2024"}},
+		{"/posts", "", nil},
+		{"/pages/3", "/pages/{id?:int}", map[string]string{"id": "3"}},
+		{"/pages", "/pages/{id?:int}", nil},
+		{"/pages/about", "", nil},
+
+		// Catch-alls take the rest of the path, which may be empty
+		{"/files/a/b/c.txt", "/files/{path...}", map[string]string{"path": "a/b/c.txt"}},
+		{"/files/", "/files/{path...}", map[string]string{"path": ""}},
+		{"/files", "", nil},
+		{"/archive/a/b", "/archive/{path...:[a-z/]+}", map[string]string{"path": "a/b"}},
+		{"/archive/2024/b", "/archive/{rest...}", map[string]string{"rest": "2024/b"}},
+
+		{"/search", "/search", nil},
+		{"/search/go", "/search/{q}", map[string]string{"q": "go"}},
+		{"/missing", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var values []string
+			leaf := r.table.match("", fasthttp.MethodGet, tt.path, &values)
+
+			if tt.route == "" {
+				if leaf != nil {
+					t.Fatalf("expected no match, got %s", leaf.route.path)
+				}
+				return
+			}
+			if leaf == nil {
+				t.Fatalf("expected %s, got no match", tt.route)
+			}
+			if leaf.route.path != tt.route {
+				t.Fatalf("expected %s, got %s", tt.route, leaf.route.path)
+			}
+
+			params := make(map[string]string, len(values))
+			for i, name := range leaf.paramNames {
+				params[name] = values[i]
+			}
+			if len(tt.params) == 0 && len(params) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Fatalf("expected params %v, got %v", tt.params, params)
+			}
+		})
+	}
+}
+
+func TestTreeFirstRegistrationWins(t *testing.T) {
+	r := NewRouter()
+	first := r.Get("/users/{id}", noopHandler)
+	r.Get("/users/{name}", noopHandler)
+
+	var values []string
+	leaf := r.table.match("", fasthttp.MethodGet, "/users/1", &values)
+	if leaf == nil || leaf.route != first {
+		t.Fatalf("expected the first registered route to match")
+	}
+}
+
+func TestParseRoutePathErrors(t *testing.T) {
+	for _, path := range []string{
+		"/users/{id",
+		"/users/x{id}",
+		"/users/{id}x",
+		"/users/{}",
+		"/files/{path...}/edit",
+		"/posts/{slug?}/{id}",
+		"/posts/{slug?}/edit",
+		"/posts/{id:[}",
+		"/posts/{id:int?}",
+	} {
+		if _, err := parseRoutePath(path); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+}
+
+func TestRouterStaticMatchDoesNotAllocate(t *testing.T) {
+	r := benchmarkRouter()
+	fastCtx := newRequestCtx(fasthttp.MethodGet, "/api/v1/res17/all")
+	ctx := &Context{fastCtx: fastCtx}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := r.Handle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		ctx.reset()
+		ctx.fastCtx = fastCtx
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations on a static hit, got %v", allocs)
+	}
+}
+
+// benchmarkRouter registers 200 routes over 40 resources
+func benchmarkRouter() *Router {
+	r := NewRouter()
+	for i := 0; i < 40; i++ {
+		base := fmt.Sprintf("/api/v1/res%02d", i)
+		r.Get(base, noopHandler)
+		r.Get(base+"/all", noopHandler)
+		r.Post(base, noopHandler)
+		r.Get(base+"/{id}", noopHandler)
+		r.Get(base+"/{id}/comments/{comment}", noopHandler)
+	}
+	return r
+}
+
+// newRequestCtx returns a request context for method and path
+func newRequestCtx(method, path string) *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(path)
+	return ctx
+}
+
+func BenchmarkRouterStatic(b *testing.B) {
+	benchmarkRouterPath(b, "/api/v1/res37/all")
+}
+
+func BenchmarkRouterParam(b *testing.B) {
+	benchmarkRouterPath(b, "/api/v1/res37/42/comments/7")
+}
+
+// benchmarkRouterPath compares the radix tree with the regular expression
+// scan it replaced
+func benchmarkRouterPath(b *testing.B, path string) {
+	b.Run("tree", func(b *testing.B) {
+		r := benchmarkRouter()
+		fastCtx := newRequestCtx(fasthttp.MethodGet, path)
+		ctx := &Context{fastCtx: fastCtx}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := r.Handle(ctx); err != nil {
+				b.Fatal(err)
+			}
+			ctx.reset()
+			ctx.fastCtx = fastCtx
+		}
+	})
+
+	b.Run("regexp", func(b *testing.B) {
+		r := newRegexpRouter(benchmarkRouter())
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, ok := r.match(fasthttp.MethodGet, path); !ok {
+				b.Fatal("no match")
+			}
+		}
+	})
+}
+
+// regexpRouter reproduces the router before the radix tree: routes are
+// scanned in order and each path is compiled to an anchored expression
+type regexpRouter struct {
+	routes map[string][]regexpRoute
+}
+
+type regexpRoute struct {
+	pattern    *regexp.Regexp
+	paramNames []string
+}
+
+func newRegexpRouter(r *Router) *regexpRouter {
+	param := regexp.MustCompile(`\{(\w+)\}`)
+	rr := &regexpRouter{routes: make(map[string][]regexpRoute)}
+
+	for _, route := range r.table.routes {
+		var names []string
+		pattern := param.ReplaceAllStringFunc(route.path, func(match string) string {
+			names = append(names, strings.Trim(match, "{}"))
+			return `([^/]+)`
+		})
+		rr.routes[route.method] = append(rr.routes[route.method], regexpRoute{
+			pattern:    regexp.MustCompile("^" + pattern + "$"),
+			paramNames: names,
+		})
+	}
+	return rr
+}
+
+func (rr *regexpRouter) match(method, path string) (map[string]string, bool) {
+	for _, route := range rr.routes[method] {
+		matches := route.pattern.FindStringSubmatch(path)
+		if matches == nil {
+			continue
+		}
+
+		params := make(map[string]string)
+		for i, name := range route.paramNames {
+			params[name] = matches[i+1]
+		}
+		return params, true
+	}
+	return nil, false
+}
@@ -3,6 +3,7 @@ package binigo
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -273,12 +274,24 @@ func (v *Validator) Errors() map[string][]string {
 
 // FirstError returns the first error message
 func (v *Validator) FirstError() string {
-	for _, messages := range v.errors {
+	return firstMessage(v.errors)
+}
+
+// firstMessage returns the first message of the alphabetically first field
+// with errors, so the result does not depend on map iteration order
+func firstMessage(errors map[string][]string) string {
+	fields := make([]string, 0, len(errors))
+	for field, messages := range errors {
 		if len(messages) > 0 {
-			return messages[0]
+			fields = append(fields, field)
 		}
 	}
-	return ""
+	if len(fields) == 0 {
+		return ""
+	}
+
+	sort.Strings(fields)
+	return errors[fields[0]][0]
 }
 
 // HasErrors checks if there are any validation errors
@@ -286,10 +299,32 @@ func (v *Validator) HasErrors() bool {
 	return len(v.errors) > 0
 }
 
+// ValidationError is returned when request data fails validation.
+// The application error handler renders it as 422 Unprocessable Entity.
+type ValidationError struct {
+	Errors map[string][]string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	if message := firstMessage(e.Errors); message != "" {
+		return "validation failed: " + message
+	}
+	return "validation failed"
+}
+
+// Err returns a *ValidationError if validation failed, nil otherwise
+func (v *Validator) Err() error {
+	if !v.HasErrors() {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
 // Context validation helper
 func (c *Context) ValidateJSON(rules map[string][]string) (*Validator, error) {
-	var data map[string]interface{}
-	if err := c.Bind(&data); err != nil {
+	data, err := c.inputData()
+	if err != nil {
 		return nil, err
 	}
 
@@ -299,22 +334,15 @@ func (c *Context) ValidateJSON(rules map[string][]string) (*Validator, error) {
 	return validator, nil
 }
 
-// ValidateRequest validates request and returns errors if any
+// ValidateRequest validates the JSON body. It returns a 400 HTTPError for
+// malformed JSON and a *ValidationError when any rule fails.
 func (c *Context) ValidateRequest(rules map[string][]string) error {
 	validator, err := c.ValidateJSON(rules)
 	if err != nil {
-		return c.Error("Invalid JSON", 400)
-	}
-
-	if validator.HasErrors() {
-		return c.Status(422).JSON(Map{
-			"success": false,
-			"message": "Validation failed",
-			"errors":  validator.Errors(),
-		})
+		return NewHTTPError(400, "Invalid JSON").WithCode("invalid_json").Wrap(err)
 	}
 
-	return nil
+	return validator.Err()
 }
 
 // Example usage in controller:
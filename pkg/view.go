@@ -0,0 +1,458 @@
+package binigo
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+// CSRFTokenKey is the context key the csrfField and csrfToken view helpers
+// read the current CSRF token from
+const CSRFTokenKey = "csrf_token"
+
+// OldInputKey is the context key for input flashed from a previous
+// request. The old view helper checks it before the current request.
+const OldInputKey = "old_input"
+
+// ViewConfig configures template rendering. Zero values use the defaults.
+type ViewConfig struct {
+	Dir       string           // template root; defaults to "resources/views"
+	Extension string           // template file extension; defaults to ".html"
+	Layout    string           // default layout, e.g. "layouts/app"; empty renders views on their own
+	Partials  string           // directory under Dir available to every view; defaults to "partials"
+	AssetURL  string           // prefix for the asset helper; defaults to "/"
+	Reload    bool             // re-parse templates when their files change; always on in development
+	Funcs     template.FuncMap // extra template functions
+}
+
+// ViewEngine renders html/template files. Views are named by their path
+// under the template root without the extension, e.g. "users/show".
+//
+// A layout includes the view with {{template "content" .}} or
+// {{block "content" .}}...{{end}}. A view either defines "content" itself,
+// along with any other blocks the layout declares, or its whole body
+// becomes the content. Templates in the partials directory are included
+// by name: {{template "partials/nav" .}}.
+//
+// Built-in helpers:
+//
+//	{{route "users.show" "id" .ID}}  URL of a named route
+//	{{asset "css/app.css"}}          URL of a public asset
+//	{{csrfField}}                    hidden input carrying the CSRF token
+//	{{csrfToken}}                    the CSRF token itself
+//	{{old "email"}}                  previously submitted input
+type ViewEngine struct {
+	config ViewConfig
+	router *Router
+
+	mu    sync.RWMutex
+	cache map[string]*viewEntry
+}
+
+// viewEntry is a parsed view and layout. The prototype is never executed,
+// so it can be cloned into per-render instances bound to a request.
+type viewEntry struct {
+	prototype *template.Template
+	name      string               // template to execute
+	sources   map[string]time.Time // files parsed, with their modification times
+	funcs     template.FuncMap     // user functions at parse time
+	instances sync.Pool
+}
+
+// viewInstance is a clone of a view whose request helpers read ctx
+type viewInstance struct {
+	tmpl *template.Template
+	ctx  *Context
+}
+
+// NewViewEngine creates a view engine
+func NewViewEngine(config ViewConfig) *ViewEngine {
+	if config.Dir == "" {
+		config.Dir = "resources/views"
+	}
+	if config.Extension == "" {
+		config.Extension = ".html"
+	}
+	if config.Partials == "" {
+		config.Partials = "partials"
+	}
+	if config.AssetURL == "" {
+		config.AssetURL = "/"
+	}
+
+	return &ViewEngine{
+		config: config,
+		cache:  make(map[string]*viewEntry),
+	}
+}
+
+// Views returns the application's view engine, created from Config.Views
+// on first use. Templates are reloaded on change in development.
+func (a *Application) Views() *ViewEngine {
+	a.mu.RLock()
+	views := a.views
+	a.mu.RUnlock()
+	if views != nil {
+		return views
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.views == nil {
+		var config ViewConfig
+		if a.config != nil {
+			config = a.config.Views
+			config.Reload = config.Reload || a.config.Environment == "development"
+		}
+		a.views = NewViewEngine(config)
+		a.views.router = a.router
+	}
+	return a.views
+}
+
+// View renders the named template as the HTML response. The layout
+// defaults to ViewConfig.Layout; pass "" to render without one.
+//
+//	return ctx.View("users/show", binigo.Map{"user": user})
+func (c *Context) View(name string, data interface{}, layout ...string) error {
+	if c.app == nil {
+		return errors.New("binigo: views require an application")
+	}
+
+	c.fastCtx.Response.Header.SetContentType("text/html; charset=utf-8")
+	c.fastCtx.ResetBody()
+
+	if err := c.app.Views().render(c, name, data, layout...); err != nil {
+		c.fastCtx.ResetBody()
+		return err
+	}
+	return nil
+}
+
+// Funcs adds template functions. Cached templates are discarded so the
+// functions are available to the next render.
+func (e *ViewEngine) Funcs(funcs template.FuncMap) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.config.Funcs == nil {
+		e.config.Funcs = make(template.FuncMap, len(funcs))
+	}
+	for name, fn := range funcs {
+		e.config.Funcs[name] = fn
+	}
+	e.cache = make(map[string]*viewEntry)
+}
+
+// render executes a view into the response body
+func (e *ViewEngine) render(c *Context, name string, data interface{}, layout ...string) error {
+	layoutName := e.config.Layout
+	if len(layout) > 0 {
+		layoutName = layout[0]
+	}
+
+	entry, err := e.entry(name, layoutName)
+	if err != nil {
+		return err
+	}
+
+	instance, _ := entry.instances.Get().(*viewInstance)
+	if instance == nil {
+		if instance, err = e.newInstance(entry); err != nil {
+			return err
+		}
+	}
+
+	instance.ctx = c
+	err = instance.tmpl.ExecuteTemplate(c.fastCtx, entry.name, data)
+	instance.ctx = nil
+	entry.instances.Put(instance)
+
+	if err != nil {
+		return fmt.Errorf("view %s: %w", name, err)
+	}
+	return nil
+}
+
+// entry returns the cached templates for a view and layout, parsing them
+// on first use and, when reloading, whenever a source file changes
+func (e *ViewEngine) entry(name, layout string) (*viewEntry, error) {
+	key := name + "\x00" + layout
+
+	e.mu.RLock()
+	entry := e.cache[key]
+	reload := e.config.Reload
+	e.mu.RUnlock()
+
+	if entry != nil && !reload {
+		return entry, nil
+	}
+
+	sources, err := e.sources(name, layout)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && !sourcesChanged(entry.sources, sources) {
+		return entry, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, err = e.parse(name, layout, sources)
+	if err != nil {
+		return nil, err
+	}
+	e.cache[key] = entry
+	return entry, nil
+}
+
+// sources lists the files a view is built from with their modification times
+func (e *ViewEngine) sources(name, layout string) (map[string]time.Time, error) {
+	sources := make(map[string]time.Time)
+
+	partials := filepath.Join(e.config.Dir, e.config.Partials)
+	err := filepath.WalkDir(partials, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == partials {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != e.config.Extension {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		sources[path] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range []string{layout, name} {
+		if view == "" {
+			continue
+		}
+		path, err := e.path(view)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("view %s: %w", view, err)
+		}
+		sources[path] = info.ModTime()
+	}
+
+	return sources, nil
+}
+
+// parse builds the template set for a view: partials, then the layout,
+// then the view, so the view's definitions override the layout's blocks
+func (e *ViewEngine) parse(name, layout string, sources map[string]time.Time) (*viewEntry, error) {
+	funcs := make(template.FuncMap, len(e.config.Funcs))
+	for name, fn := range e.config.Funcs {
+		funcs[name] = fn
+	}
+	root := template.New("").Funcs(e.funcs(nil, funcs))
+
+	partials := filepath.Join(e.config.Dir, e.config.Partials)
+	for path := range sources {
+		if strings.HasPrefix(path, partials+string(filepath.Separator)) {
+			if err := e.parseFile(root, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The "content" tree before the view is parsed, which a layout's
+	// {{block "content" .}} provides, tells whether the view redefined it
+	var inheritedContent *parse.Tree
+	for _, view := range []string{layout, name} {
+		if view == "" {
+			continue
+		}
+		inheritedContent = contentTree(root)
+		path, _ := e.path(view)
+		if err := e.parseFile(root, path); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &viewEntry{prototype: root, name: name, sources: sources, funcs: funcs}
+	if layout != "" {
+		// Without its own "content" definition the whole view is the content
+		if tree := contentTree(root); tree == nil || tree == inheritedContent {
+			if _, err := root.AddParseTree("content", root.Lookup(name).Tree); err != nil {
+				return nil, err
+			}
+		}
+		entry.name = layout
+	}
+
+	return entry, nil
+}
+
+// contentTree returns the parse tree of the "content" template, if defined
+func contentTree(root *template.Template) *parse.Tree {
+	if content := root.Lookup("content"); content != nil {
+		return content.Tree
+	}
+	return nil
+}
+
+// parseFile adds a template named by its path relative to the root
+func (e *ViewEngine) parseFile(root *template.Template, path string) error {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(e.config.Dir, path)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSuffix(filepath.ToSlash(rel), e.config.Extension)
+
+	if _, err := root.New(name).Parse(string(text)); err != nil {
+		return fmt.Errorf("view %s: %w", name, err)
+	}
+	return nil
+}
+
+// path returns the file for a view name
+func (e *ViewEngine) path(name string) (string, error) {
+	if hasDotDotSegment(name) {
+		return "", fmt.Errorf("view %s: invalid name", name)
+	}
+	return filepath.Join(e.config.Dir, filepath.FromSlash(name)+e.config.Extension), nil
+}
+
+// newInstance clones the prototype with request helpers bound to the instance
+func (e *ViewEngine) newInstance(entry *viewEntry) (*viewInstance, error) {
+	tmpl, err := entry.prototype.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &viewInstance{tmpl: tmpl}
+	tmpl.Funcs(e.funcs(instance, entry.funcs))
+	return instance, nil
+}
+
+// funcs returns the helpers followed by the user's functions. Request
+// helpers read the instance's context; with a nil instance they are
+// placeholders for parsing.
+func (e *ViewEngine) funcs(instance *viewInstance, user template.FuncMap) template.FuncMap {
+	funcs := template.FuncMap{
+		"route":     e.routeURL,
+		"asset":     e.assetURL,
+		"csrfToken": func() string { return instance.csrfToken() },
+		"csrfField": func() template.HTML { return instance.csrfField() },
+		"old":       func(name string, defaultValue ...string) string { return instance.old(name, defaultValue...) },
+	}
+
+	for name, fn := range user {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// routeURL builds a named route's URL from a Map or key/value pairs
+func (e *ViewEngine) routeURL(name string, params ...interface{}) (string, error) {
+	if e.router == nil {
+		return "", fmt.Errorf("route %s: view engine has no router", name)
+	}
+
+	values := Map{}
+	for i := 0; i < len(params); i++ {
+		switch param := params[i].(type) {
+		case Map:
+			for key, value := range param {
+				values[key] = value
+			}
+		case map[string]interface{}:
+			for key, value := range param {
+				values[key] = value
+			}
+		case string:
+			if i+1 >= len(params) {
+				return "", fmt.Errorf("route %s: missing value for %s", name, param)
+			}
+			values[param] = params[i+1]
+			i++
+		default:
+			return "", fmt.Errorf("route %s: unexpected parameter %v", name, param)
+		}
+	}
+
+	return e.router.URL(name, values)
+}
+
+// assetURL joins path onto the asset prefix
+func (e *ViewEngine) assetURL(path string) string {
+	return strings.TrimSuffix(e.config.AssetURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func (v *viewInstance) csrfToken() string {
+	if v == nil || v.ctx == nil {
+		return ""
+	}
+	return v.ctx.GetString(CSRFTokenKey)
+}
+
+func (v *viewInstance) csrfField() template.HTML {
+	return template.HTML(`<input type="hidden" name="_token" value="` + template.HTMLEscapeString(v.csrfToken()) + `">`)
+}
+
+// old returns input flashed under OldInputKey, then the current request's
+// form, query or JSON value, so a form re-rendered after failed validation
+// keeps what the user typed
+func (v *viewInstance) old(name string, defaultValue ...string) string {
+	if v != nil && v.ctx != nil {
+		c := v.ctx
+		if flashed, ok := c.Get(OldInputKey).(Map); ok {
+			if value, ok := lookupInput(flashed, name); ok && value != nil {
+				return fmt.Sprint(value)
+			}
+		}
+		if value := c.fastCtx.FormValue(name); value != nil {
+			return string(value)
+		}
+		if strings.Contains(string(c.fastCtx.Request.Header.ContentType()), "json") {
+			if value := c.InputString(name); value != "" {
+				return value
+			}
+		}
+	}
+
+	if len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return ""
+}
+
+// sourcesChanged reports whether the files or their modification times differ
+func sourcesChanged(old, current map[string]time.Time) bool {
+	if len(old) != len(current) {
+		return true
+	}
+	for path, modTime := range current {
+		if previous, ok := old[path]; !ok || !previous.Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
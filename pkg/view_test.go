@@ -0,0 +1,120 @@
+package binigo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+)
+
+// writeViews writes template files under dir
+func writeViews(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, text := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestViewLayouts(t *testing.T) {
+	dir := t.TempDir()
+	writeViews(t, dir, map[string]string{
+		"layouts/app.html":    `<title>{{block "title" .}}App{{end}}</title>{{template "partials/nav" .}}<main>{{block "content" .}}default{{end}}</main>`,
+		"layouts/plain.html":  `<div>{{template "content" .}}</div>`,
+		"partials/nav.html":   `<nav>{{asset "css/app.css"}}</nav>`,
+		"home.html":           `<p>Hello {{.name}}</p>`,
+		"users/show.html":     `{{define "title"}}{{.name}}{{end}}{{define "content"}}<h1>{{.name}}</h1>{{end}}`,
+		"users/edit.html":     `{{define "title"}}Edit {{.name}}{{end}}<form>{{.name}}</form>`,
+		"users/link.html":     `<a href="{{route "users.show" "id" 7}}">{{.name}}</a>`,
+		"users/fragment.html": `<span>{{.name}}</span>`,
+	})
+
+	app := binigo.NewApplication(&binigo.Config{
+		Views: binigo.ViewConfig{Dir: dir, Layout: "layouts/app", AssetURL: "/static/"},
+	})
+	app.Get("/users/{id}", func(ctx *binigo.Context) error { return nil }).Name("users.show")
+	app.Get("/render/{view...}", func(ctx *binigo.Context) error {
+		layout := []string{}
+		if ctx.Query("layout") != "" {
+			layout = append(layout, ctx.Query("layout"))
+		} else if ctx.Query("bare") != "" {
+			layout = append(layout, "")
+		}
+		return ctx.View(ctx.Param("view"), binigo.Map{"name": "Ada <3"}, layout...)
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		// A view without a "content" definition replaces the layout's block
+		{"/render/home", `<title>App</title><nav>/static/css/app.css</nav><main><p>Hello Ada &lt;3</p></main>`},
+		{"/render/users/show", `<title>Ada &lt;3</title><nav>/static/css/app.css</nav><main><h1>Ada &lt;3</h1></main>`},
+		{"/render/users/edit", `<title>Edit Ada &lt;3</title><nav>/static/css/app.css</nav><main><form>Ada &lt;3</form></main>`},
+		{"/render/users/link", `<title>App</title><nav>/static/css/app.css</nav><main><a href="/users/7">Ada &lt;3</a></main>`},
+		{"/render/home?layout=layouts/plain", `<div><p>Hello Ada &lt;3</p></div>`},
+		{"/render/users/show?layout=layouts/plain", `<div><h1>Ada &lt;3</h1></div>`},
+		{"/render/users/fragment?bare=1", `<span>Ada &lt;3</span>`},
+	}
+
+	ta := binigotest.New(t, app)
+	for _, tt := range tests {
+		if body := string(ta.Test().Get(tt.path).Expect(200).Header("Content-Type", "text/html; charset=utf-8").Body()); body != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.want, body)
+		}
+	}
+
+	ta.Test().Get("/render/missing").Expect(500)
+}
+
+func TestViewReload(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		reload bool
+		want   string
+	}{
+		{"cached", false, "[v1home v1]"},
+		{"reload", true, "[v2home v2]"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeViews(t, dir, map[string]string{
+				"layouts/app.html":    `[{{template "partials/badge" .}}{{block "content" .}}{{end}}]`,
+				"partials/badge.html": `v1`,
+				"home.html":           `home v1`,
+			})
+
+			app := binigo.NewApplication(&binigo.Config{
+				Views: binigo.ViewConfig{Dir: dir, Layout: "layouts/app", Reload: tt.reload},
+			})
+			app.Get("/", func(ctx *binigo.Context) error {
+				return ctx.View("home", nil)
+			})
+			ta := binigotest.New(t, app)
+			ta.Test().Get("/").Expect(200).Contains("[v1home v1]")
+
+			writeViews(t, dir, map[string]string{
+				"partials/badge.html": `v2`,
+				"home.html":           `home v2`,
+			})
+			later := time.Now().Add(time.Minute)
+			for _, name := range []string{"partials/badge.html", "home.html"} {
+				if err := os.Chtimes(filepath.Join(dir, name), later, later); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if body := string(ta.Test().Get("/").Expect(200).Body()); body != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,625 @@
+package binigo
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/valyala/fasthttp"
+)
+
+// WebSocket message types, matching the RFC 6455 opcodes
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// WebSocket close codes
+const (
+	CloseNormalClosure    = 1000
+	CloseGoingAway        = 1001
+	CloseProtocolError    = 1002
+	CloseUnsupportedData  = 1003
+	CloseNoStatusReceived = 1005
+	CloseAbnormalClosure  = 1006
+	CloseInvalidPayload   = 1007
+	ClosePolicyViolation  = 1008
+	CloseMessageTooBig    = 1009
+	CloseInternalError    = 1011
+)
+
+// websocketGUID is appended to the client key to compute Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket defaults used when WebSocketConfig fields are zero
+const (
+	DefaultWebSocketReadLimit    = 1 << 20
+	DefaultWebSocketPingInterval = 30 * time.Second
+	DefaultWebSocketPongTimeout  = 10 * time.Second
+	DefaultWebSocketWriteTimeout = 10 * time.Second
+)
+
+// WebSocketHandler handles an upgraded connection. The connection is closed
+// when the handler returns; a returned error closes it with CloseInternalError.
+type WebSocketHandler func(conn *WebSocketConn) error
+
+// WebSocketConfig holds per-connection limits and upgrade options
+type WebSocketConfig struct {
+	ReadLimit    int64         // maximum message size in bytes
+	PingInterval time.Duration // how often pings are sent; negative disables them
+	PongTimeout  time.Duration // how long to wait for any frame after a ping
+	WriteTimeout time.Duration // deadline for writing a single message
+
+	// Subprotocols lists supported subprotocols in order of preference
+	Subprotocols []string
+
+	// CheckOrigin decides whether the upgrade is allowed. By default the
+	// Origin header, when present, must match the Host header.
+	CheckOrigin func(ctx *Context) bool
+}
+
+// CloseError is returned by read methods when the peer closes the connection
+type CloseError struct {
+	Code int
+	Text string
+}
+
+// Error implements the error interface
+func (e *CloseError) Error() string {
+	if e.Text != "" {
+		return fmt.Sprintf("websocket: closed with code %d: %s", e.Code, e.Text)
+	}
+	return fmt.Sprintf("websocket: closed with code %d", e.Code)
+}
+
+// ErrWebSocketClosed is returned when writing to a connection that is closed
+var ErrWebSocketClosed = errors.New("websocket: connection closed")
+
+// WebSocket registers a GET route that upgrades to a WebSocket connection.
+// Route and group middleware run before the upgrade, so authentication can
+// reject the request with a normal HTTP response.
+func (r *Router) WebSocket(path string, handler WebSocketHandler, config ...WebSocketConfig) *Route {
+	var cfg WebSocketConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.ReadLimit <= 0 {
+		cfg.ReadLimit = DefaultWebSocketReadLimit
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = DefaultWebSocketPingInterval
+	}
+	if cfg.PongTimeout <= 0 {
+		cfg.PongTimeout = DefaultWebSocketPongTimeout
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWebSocketWriteTimeout
+	}
+	if cfg.CheckOrigin == nil {
+		cfg.CheckOrigin = sameOrigin
+	}
+
+	return r.Get(path, func(ctx *Context) error {
+		return ctx.upgradeWebSocket(handler, cfg)
+	})
+}
+
+// WebSocket registers a WebSocket route. See Router.WebSocket.
+func (a *Application) WebSocket(path string, handler WebSocketHandler, config ...WebSocketConfig) *Route {
+	return a.router.WebSocket(path, handler, config...)
+}
+
+// IsWebSocket reports whether the request asks for a WebSocket upgrade
+func (c *Context) IsWebSocket() bool {
+	return headerHasToken(c.Header("Connection"), "upgrade") &&
+		headerHasToken(c.Header("Upgrade"), "websocket")
+}
+
+// upgradeWebSocket validates the handshake, answers with 101 Switching
+// Protocols and hands the hijacked connection to handler
+func (c *Context) upgradeWebSocket(handler WebSocketHandler, cfg WebSocketConfig) error {
+	if !c.IsWebSocket() {
+		return NewHTTPError(fasthttp.StatusBadRequest, "WebSocket upgrade required").WithCode("websocket_upgrade_required")
+	}
+
+	if c.Header("Sec-WebSocket-Version") != "13" {
+		c.SetHeader("Sec-WebSocket-Version", "13")
+		return NewHTTPError(fasthttp.StatusUpgradeRequired, "Unsupported WebSocket version").WithCode("websocket_version")
+	}
+
+	key := c.Header("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return NewHTTPError(fasthttp.StatusBadRequest, "Invalid Sec-WebSocket-Key").WithCode("websocket_key")
+	}
+
+	if !cfg.CheckOrigin(c) {
+		return NewHTTPError(fasthttp.StatusForbidden, "Origin not allowed").WithCode("websocket_origin")
+	}
+
+	subprotocol := selectSubprotocol(c.Header("Sec-WebSocket-Protocol"), cfg.Subprotocols)
+
+	// The Context is reused once this handler returns, so copy what the
+	// connection needs from the request now
+	conn := &WebSocketConn{
+		config:      cfg,
+		subprotocol: subprotocol,
+		json:        c.jsonCodec(),
+		params:      make(map[string]string, len(c.params)),
+		values:      make(map[string]interface{}, len(c.store)),
+	}
+	for name, value := range c.params {
+		conn.params[name] = value
+	}
+	for key, value := range c.store {
+		conn.values[key] = value
+	}
+	c.fastCtx.Request.Header.CopyTo(&conn.header)
+	c.fastCtx.Request.URI().CopyTo(&conn.uri)
+
+	var shutdown context.Context
+	if c.app != nil {
+		shutdown = c.app.shutdownContext()
+	}
+
+	c.fastCtx.Response.Header.Set("Upgrade", "websocket")
+	c.fastCtx.Response.Header.Set("Connection", "Upgrade")
+	c.fastCtx.Response.Header.Set("Sec-WebSocket-Accept", websocketAccept(key))
+	if subprotocol != "" {
+		c.fastCtx.Response.Header.Set("Sec-WebSocket-Protocol", subprotocol)
+	}
+	c.fastCtx.SetStatusCode(fasthttp.StatusSwitchingProtocols)
+
+	c.fastCtx.Hijack(func(netConn net.Conn) {
+		conn.serve(netConn, shutdown, handler)
+	})
+
+	return nil
+}
+
+// WebSocketConn is an upgraded WebSocket connection. Reads must happen on
+// one goroutine; writes are safe from any number of goroutines.
+type WebSocketConn struct {
+	conn        net.Conn
+	reader      *bufio.Reader
+	config      WebSocketConfig
+	subprotocol string
+	json        JSONCodec
+	params      map[string]string
+	values      map[string]interface{}
+	header      fasthttp.RequestHeader
+	uri         fasthttp.URI
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	writeMu   sync.Mutex
+	closeSent bool
+}
+
+// serve runs handler on the hijacked connection and closes it afterwards
+func (ws *WebSocketConn) serve(netConn net.Conn, shutdown context.Context, handler WebSocketHandler) {
+	ws.conn = netConn
+	ws.reader = bufio.NewReader(netConn)
+	ws.ctx, ws.cancel = context.WithCancel(context.Background())
+	defer ws.cancel()
+
+	// Clear deadlines left by the HTTP server's read and write timeouts
+	_ = netConn.SetDeadline(time.Time{})
+	ws.extendReadDeadline()
+
+	if shutdown != nil {
+		stop := context.AfterFunc(shutdown, func() {
+			_ = ws.Close(CloseGoingAway, "server shutting down")
+		})
+		defer stop()
+	}
+
+	if ws.config.PingInterval > 0 {
+		go ws.pingLoop()
+	}
+
+	err := handler(ws)
+
+	var closeErr *CloseError
+	switch {
+	case err == nil, errors.As(err, &closeErr), errors.Is(err, ErrWebSocketClosed), errors.Is(err, net.ErrClosed):
+		_ = ws.Close(CloseNormalClosure, "")
+	default:
+		log.Printf("WebSocket handler error: %v", err)
+		_ = ws.Close(CloseInternalError, "")
+	}
+}
+
+// Param gets a route parameter captured before the upgrade
+func (ws *WebSocketConn) Param(name string) string {
+	return ws.params[name]
+}
+
+// Query gets a query parameter from the upgrade request
+func (ws *WebSocketConn) Query(name string) string {
+	return string(ws.uri.QueryArgs().Peek(name))
+}
+
+// Header gets a header from the upgrade request
+func (ws *WebSocketConn) Header(name string) string {
+	return string(ws.header.Peek(name))
+}
+
+// Get returns a value stored on the Context by middleware before the upgrade
+func (ws *WebSocketConn) Get(key string) interface{} {
+	return ws.values[key]
+}
+
+// Subprotocol returns the negotiated subprotocol, if any
+func (ws *WebSocketConn) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// RemoteAddr returns the client address
+func (ws *WebSocketConn) RemoteAddr() net.Addr {
+	return ws.conn.RemoteAddr()
+}
+
+// Context returns a context that is cancelled when the connection closes
+func (ws *WebSocketConn) Context() context.Context {
+	return ws.ctx
+}
+
+// ReadMessage reads the next text or binary message, answering pings and
+// reassembling fragmented messages. It returns a *CloseError when the peer
+// closes the connection.
+func (ws *WebSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	var message []byte
+
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, ws.failRead(err)
+		}
+		ws.extendReadDeadline()
+
+		switch opcode {
+		case PingMessage:
+			if err := ws.writeFrame(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			return 0, nil, ws.handleClose(payload)
+		case 0:
+			if messageType == 0 {
+				return 0, nil, ws.protocolError("unexpected continuation frame")
+			}
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, ws.protocolError("new message started before the previous one finished")
+			}
+			messageType = opcode
+		default:
+			return 0, nil, ws.protocolError(fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if int64(len(message)+len(payload)) > ws.config.ReadLimit {
+			_ = ws.Close(CloseMessageTooBig, "message too big")
+			return 0, nil, &CloseError{Code: CloseMessageTooBig, Text: "message too big"}
+		}
+		message = append(message, payload...)
+
+		if fin {
+			break
+		}
+	}
+
+	if messageType == TextMessage && !utf8.Valid(message) {
+		_ = ws.Close(CloseInvalidPayload, "invalid UTF-8")
+		return 0, nil, &CloseError{Code: CloseInvalidPayload, Text: "invalid UTF-8"}
+	}
+
+	return messageType, message, nil
+}
+
+// ReadText reads the next message as a string
+func (ws *WebSocketConn) ReadText() (string, error) {
+	_, data, err := ws.ReadMessage()
+	return string(data), err
+}
+
+// ReadJSON reads the next message and decodes it as JSON into v
+func (ws *WebSocketConn) ReadJSON(v interface{}) error {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return ws.json.Unmarshal(data, v)
+}
+
+// WriteMessage sends a text or binary message
+func (ws *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != TextMessage && messageType != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return ws.writeFrame(messageType, data)
+}
+
+// WriteText sends a text message
+func (ws *WebSocketConn) WriteText(text string) error {
+	return ws.writeFrame(TextMessage, []byte(text))
+}
+
+// WriteBinary sends a binary message
+func (ws *WebSocketConn) WriteBinary(data []byte) error {
+	return ws.writeFrame(BinaryMessage, data)
+}
+
+// WriteJSON encodes v as JSON and sends it as a text message
+func (ws *WebSocketConn) WriteJSON(v interface{}) error {
+	data, err := ws.json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ws.writeFrame(TextMessage, data)
+}
+
+// Ping sends a ping frame; the client answers with a pong
+func (ws *WebSocketConn) Ping(data []byte) error {
+	return ws.writeFrame(PingMessage, data)
+}
+
+// Close sends a close frame with code and reason and closes the connection
+func (ws *WebSocketConn) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+
+	err := ws.writeFrame(CloseMessage, payload)
+	ws.cancel()
+	_ = ws.conn.Close()
+
+	if errors.Is(err, ErrWebSocketClosed) {
+		return nil
+	}
+	return err
+}
+
+// handleClose answers a close frame from the peer and reports it
+func (ws *WebSocketConn) handleClose(payload []byte) error {
+	closeErr := &CloseError{Code: CloseNoStatusReceived}
+
+	switch {
+	case len(payload) == 1:
+		return ws.protocolError("invalid close frame")
+	case len(payload) >= 2:
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Text = string(payload[2:])
+		if !validCloseCode(closeErr.Code) {
+			return ws.protocolError("invalid close code")
+		}
+		if !utf8.ValidString(closeErr.Text) {
+			_ = ws.Close(CloseInvalidPayload, "invalid UTF-8")
+			return closeErr
+		}
+	}
+
+	code := closeErr.Code
+	if code == CloseNoStatusReceived {
+		code = CloseNormalClosure
+	}
+	_ = ws.Close(code, "")
+	return closeErr
+}
+
+// protocolError closes the connection after a protocol violation
+func (ws *WebSocketConn) protocolError(reason string) error {
+	_ = ws.Close(CloseProtocolError, reason)
+	return &CloseError{Code: CloseProtocolError, Text: reason}
+}
+
+// failRead maps a transport failure to a close error
+func (ws *WebSocketConn) failRead(err error) error {
+	var closeErr *CloseError
+	if errors.As(err, &closeErr) {
+		return err
+	}
+
+	ws.cancel()
+	_ = ws.conn.Close()
+
+	if ws.wasCloseSent() {
+		return ErrWebSocketClosed
+	}
+	return &CloseError{Code: CloseAbnormalClosure, Text: err.Error()}
+}
+
+// readFrame reads and unmasks a single frame
+func (ws *WebSocketConn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(ws.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0f)
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7f)
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, ws.protocolError("reserved bits set")
+	}
+	if !masked {
+		return false, 0, nil, ws.protocolError("client frames must be masked")
+	}
+
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err = io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err = io.ReadFull(ws.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	if opcode >= CloseMessage && (length > 125 || !fin) {
+		return false, 0, nil, ws.protocolError("invalid control frame")
+	}
+	if length < 0 || length > ws.config.ReadLimit {
+		_ = ws.Close(CloseMessageTooBig, "message too big")
+		return false, 0, nil, &CloseError{Code: CloseMessageTooBig, Text: "message too big"}
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame. Nothing may be written after
+// a close frame.
+func (ws *WebSocketConn) writeFrame(opcode int, payload []byte) error {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+
+	if ws.closeSent {
+		return ErrWebSocketClosed
+	}
+	if opcode == CloseMessage {
+		ws.closeSent = true
+	}
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|byte(opcode))
+
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+	frame = append(frame, payload...)
+
+	_ = ws.conn.SetWriteDeadline(time.Now().Add(ws.config.WriteTimeout))
+	_, err := ws.conn.Write(frame)
+	return err
+}
+
+// wasCloseSent reports whether a close frame has been written
+func (ws *WebSocketConn) wasCloseSent() bool {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	return ws.closeSent
+}
+
+// extendReadDeadline gives the peer until the next ping plus the pong
+// timeout to send something
+func (ws *WebSocketConn) extendReadDeadline() {
+	if ws.config.PingInterval > 0 {
+		_ = ws.conn.SetReadDeadline(time.Now().Add(ws.config.PingInterval + ws.config.PongTimeout))
+	}
+}
+
+// pingLoop sends keepalive pings until the connection closes
+func (ws *WebSocketConn) pingLoop() {
+	ticker := time.NewTicker(ws.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ws.Ping(nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for key
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// selectSubprotocol picks the first supported protocol the client offered
+func selectSubprotocol(requested string, supported []string) string {
+	for _, protocol := range supported {
+		if headerHasToken(requested, protocol) {
+			return protocol
+		}
+	}
+	return ""
+}
+
+// headerHasToken reports whether a comma-separated header contains token
+func headerHasToken(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameOrigin allows requests without an Origin header and those whose
+// Origin host matches the Host header
+func sameOrigin(ctx *Context) bool {
+	origin := ctx.Header("Origin")
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, string(ctx.fastCtx.Host()))
+}
+
+// validCloseCode reports whether a peer may send code in a close frame
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1011:
+		return true
+	case code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
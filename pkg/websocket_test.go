@@ -0,0 +1,404 @@
+package binigo_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	binigo "github.com/Chisonm/binigo/pkg"
+	"github.com/Chisonm/binigo/pkg/binigotest"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// rfcKey and rfcAccept are the handshake example from RFC 6455 section 1.3
+const (
+	rfcKey    = "dGhlIHNhbXBsZSBub25jZQ=="
+	rfcAccept = "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+)
+
+// wsClient is the client side of an upgraded connection
+type wsClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWebSocket serves app on an in-memory listener and upgrades path
+func dialWebSocket(t *testing.T, app *binigo.Application, path string) *wsClient {
+	t.Helper()
+
+	ln := fasthttputil.NewInmemoryListener()
+	server := &fasthttp.Server{Handler: app.Handler()}
+	go server.Serve(ln)
+	t.Cleanup(func() { ln.Close() })
+
+	conn, err := ln.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: %s\r\n\r\n", path, rfcKey)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+
+	return &wsClient{t: t, conn: conn, reader: reader}
+}
+
+// send writes a masked client frame
+func (c *wsClient) send(fin bool, opcode int, payload []byte) {
+	c.t.Helper()
+	c.write(fin, opcode, payload, true)
+}
+
+// write writes a frame, masked with a fixed key unless masked is false
+func (c *wsClient) write(fin bool, opcode int, payload []byte, masked bool) {
+	c.t.Helper()
+
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+
+	if masked {
+		mask := [4]byte{0x12, 0x34, 0x56, 0x78}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// read reads one unmasked server frame
+func (c *wsClient) read() (fin bool, opcode int, payload []byte) {
+	c.t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	if header[1]&0x80 != 0 {
+		c.t.Fatal("expected server frames to be unmasked")
+	}
+
+	length := uint64(header[1] & 0x7f)
+	if length >= 126 {
+		extended := make([]byte, 2)
+		if length == 127 {
+			extended = make([]byte, 8)
+		}
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			c.t.Fatal(err)
+		}
+
+		length = 0
+		for _, b := range extended {
+			length = length<<8 | uint64(b)
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	return header[0]&0x80 != 0, int(header[0] & 0x0f), payload
+}
+
+// expectClose reads a close frame and checks its code
+func (c *wsClient) expectClose(code int) {
+	c.t.Helper()
+
+	_, opcode, payload := c.read()
+	if opcode != binigo.CloseMessage || len(payload) < 2 {
+		c.t.Fatalf("expected a close frame, got opcode %d %q", opcode, payload)
+	}
+	if got := int(binary.BigEndian.Uint16(payload)); got != code {
+		c.t.Fatalf("expected close code %d, got %d (%s)", code, got, payload[2:])
+	}
+}
+
+// closePayload builds a close frame payload
+func closePayload(code int, reason string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+}
+
+// echoApp echoes messages on /ws and reports the error that ended the read loop
+func echoApp(config binigo.WebSocketConfig) (*binigo.Application, chan error) {
+	done := make(chan error, 1)
+
+	app := binigo.NewApplication(nil)
+	app.WebSocket("/ws", func(conn *binigo.WebSocketConn) error {
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				done <- err
+				return err
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				done <- err
+				return err
+			}
+		}
+	}, config)
+	return app, done
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	app := binigo.NewApplication(nil)
+	app.WebSocket("/ws", func(conn *binigo.WebSocketConn) error { return nil }, binigo.WebSocketConfig{
+		Subprotocols: []string{"v2.chat", "v1.chat"},
+	})
+
+	upgrade := map[string]string{
+		"Connection":            "keep-alive, Upgrade",
+		"Upgrade":               "websocket",
+		"Sec-WebSocket-Version": "13",
+		"Sec-WebSocket-Key":     rfcKey,
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string // overrides of upgrade, empty values remove the header
+		status  int
+		code    string
+	}{
+		{"valid", nil, 101, ""},
+		{"plain GET", map[string]string{"Connection": "", "Upgrade": ""}, 400, "websocket_upgrade_required"},
+		{"bad version", map[string]string{"Sec-WebSocket-Version": "8"}, 426, "websocket_version"},
+		{"missing key", map[string]string{"Sec-WebSocket-Key": ""}, 400, "websocket_key"},
+		{"short key", map[string]string{"Sec-WebSocket-Key": "c2hvcnQ="}, 400, "websocket_key"},
+		{"malformed key", map[string]string{"Sec-WebSocket-Key": "not base64!"}, 400, "websocket_key"},
+		{"foreign origin", map[string]string{"Origin": "https://evil.example"}, 403, "websocket_origin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := binigotest.New(t, app).Test().Get("/ws")
+			for name, value := range upgrade {
+				if override, ok := tt.headers[name]; ok {
+					value = override
+				}
+				if value != "" {
+					req.Header(name, value)
+				}
+			}
+			if origin := tt.headers["Origin"]; origin != "" {
+				req.Header("Origin", origin)
+			}
+
+			resp := req.Expect(tt.status)
+			if tt.code != "" {
+				resp.JSONPath("code", tt.code)
+			}
+			if tt.status == 426 {
+				resp.Header("Sec-WebSocket-Version", "13")
+			}
+		})
+	}
+
+	binigotest.New(t, app).Test().Get("/ws").
+		Header("Connection", "Upgrade").
+		Header("Upgrade", "WebSocket").
+		Header("Sec-WebSocket-Version", "13").
+		Header("Sec-WebSocket-Key", rfcKey).
+		Header("Sec-WebSocket-Protocol", "v1.chat, v2.chat").
+		Expect(101).
+		Header("Upgrade", "websocket").
+		Header("Sec-WebSocket-Accept", rfcAccept).
+		Header("Sec-WebSocket-Protocol", "v2.chat")
+}
+
+func TestWebSocketMessages(t *testing.T) {
+	app, done := echoApp(binigo.WebSocketConfig{PingInterval: -1})
+	client := dialWebSocket(t, app, "/ws")
+
+	client.send(true, binigo.TextMessage, []byte("hello"))
+	if fin, opcode, payload := client.read(); !fin || opcode != binigo.TextMessage || string(payload) != "hello" {
+		t.Fatalf("expected the text echoed, got %v %d %q", fin, opcode, payload)
+	}
+
+	// Control frames may arrive between the fragments of a message
+	client.send(false, binigo.TextMessage, []byte("hel"))
+	client.send(true, binigo.PingMessage, []byte("are you there"))
+	client.send(false, 0, []byte("lo "))
+	client.send(true, 0, []byte("world"))
+	if _, opcode, payload := client.read(); opcode != binigo.PongMessage || string(payload) != "are you there" {
+		t.Fatalf("expected a pong with the ping payload, got %d %q", opcode, payload)
+	}
+	if _, opcode, payload := client.read(); opcode != binigo.TextMessage || string(payload) != "hello world" {
+		t.Fatalf("expected the reassembled message, got %d %q", opcode, payload)
+	}
+
+	// Payloads over 125 bytes use the extended length
+	large := []byte(strings.Repeat("x", 70000))
+	client.send(true, binigo.BinaryMessage, large)
+	if _, opcode, payload := client.read(); opcode != binigo.BinaryMessage || string(payload) != string(large) {
+		t.Fatalf("expected the binary message echoed, got %d with %d bytes", opcode, len(payload))
+	}
+
+	client.send(true, binigo.CloseMessage, closePayload(binigo.CloseGoingAway, "bye"))
+	client.expectClose(binigo.CloseGoingAway)
+
+	var closeErr *binigo.CloseError
+	if err := <-done; !errors.As(err, &closeErr) || closeErr.Code != binigo.CloseGoingAway || closeErr.Text != "bye" {
+		t.Fatalf("expected the peer's close error, got %v", err)
+	}
+}
+
+func TestWebSocketCloseCodes(t *testing.T) {
+	type frame struct {
+		fin     bool
+		opcode  int
+		payload []byte
+	}
+
+	tests := []struct {
+		name     string
+		frames   []frame
+		unmasked bool
+		close    int // code the server closes with
+		err      int // code of the CloseError returned by ReadMessage
+	}{
+		{
+			name:   "close without status",
+			frames: []frame{{true, binigo.CloseMessage, nil}},
+			close:  binigo.CloseNormalClosure,
+			err:    binigo.CloseNoStatusReceived,
+		},
+		{
+			name:     "unmasked frame",
+			frames:   []frame{{true, binigo.TextMessage, []byte("hi")}},
+			unmasked: true,
+			close:    binigo.CloseProtocolError,
+			err:      binigo.CloseProtocolError,
+		},
+		{
+			name:   "continuation without a message",
+			frames: []frame{{true, 0, []byte("lost")}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "new message inside a fragmented one",
+			frames: []frame{{false, binigo.TextMessage, []byte("a")}, {true, binigo.TextMessage, []byte("b")}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "fragmented control frame",
+			frames: []frame{{false, binigo.PingMessage, []byte("p")}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "unknown opcode",
+			frames: []frame{{true, 3, nil}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "one byte close payload",
+			frames: []frame{{true, binigo.CloseMessage, []byte{3}}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "reserved close code",
+			frames: []frame{{true, binigo.CloseMessage, closePayload(binigo.CloseNoStatusReceived, "")}},
+			close:  binigo.CloseProtocolError,
+			err:    binigo.CloseProtocolError,
+		},
+		{
+			name:   "invalid UTF-8",
+			frames: []frame{{true, binigo.TextMessage, []byte{0xff, 0xfe}}},
+			close:  binigo.CloseInvalidPayload,
+			err:    binigo.CloseInvalidPayload,
+		},
+		{
+			name:   "frame over the read limit",
+			frames: []frame{{true, binigo.BinaryMessage, make([]byte, 17)}},
+			close:  binigo.CloseMessageTooBig,
+			err:    binigo.CloseMessageTooBig,
+		},
+		{
+			name:   "fragments over the read limit",
+			frames: []frame{{false, binigo.BinaryMessage, make([]byte, 10)}, {true, 0, make([]byte, 10)}},
+			close:  binigo.CloseMessageTooBig,
+			err:    binigo.CloseMessageTooBig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, done := echoApp(binigo.WebSocketConfig{PingInterval: -1, ReadLimit: 16})
+			client := dialWebSocket(t, app, "/ws")
+
+			for _, f := range tt.frames {
+				client.write(f.fin, f.opcode, f.payload, !tt.unmasked)
+			}
+			client.expectClose(tt.close)
+
+			var closeErr *binigo.CloseError
+			if err := <-done; !errors.As(err, &closeErr) || closeErr.Code != tt.err {
+				t.Fatalf("expected a close error with code %d, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestWebSocketHandlerError(t *testing.T) {
+	app := binigo.NewApplication(nil)
+	app.WebSocket("/ws", func(conn *binigo.WebSocketConn) error {
+		return conn.WriteJSON(binigo.Map{"greeting": "hi " + conn.Query("name")})
+	}, binigo.WebSocketConfig{PingInterval: -1})
+	app.WebSocket("/fail", func(conn *binigo.WebSocketConn) error {
+		return errors.New("handler failed")
+	}, binigo.WebSocketConfig{PingInterval: -1})
+
+	client := dialWebSocket(t, app, "/ws?name=ada")
+	if _, opcode, payload := client.read(); opcode != binigo.TextMessage || string(payload) != `{"greeting":"hi ada"}` {
+		t.Fatalf("expected a JSON greeting, got %d %q", opcode, payload)
+	}
+	client.expectClose(binigo.CloseNormalClosure)
+
+	dialWebSocket(t, app, "/fail").expectClose(binigo.CloseInternalError)
+}